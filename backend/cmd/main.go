@@ -20,6 +20,7 @@ import (
 	"strawberry/pkg/redis"
 	"syscall"
 	"time"
+	_ "time/tzdata"
 
 	"go.uber.org/zap"
 )
@@ -37,6 +38,11 @@ func main() {
 
 	cfg := config.MustLoad()
 
+	loc, err := time.LoadLocation(cfg.App.TimeZone)
+	if err != nil {
+		log.Fatal("unknown time zone", zap.String("tz", cfg.App.TimeZone), zap.Error(err))
+	}
+
 	pool, err := db.NewPGXPool(ctx, db.Config{
 		User:     cfg.Database.User,
 		Password: cfg.Database.Password,
@@ -62,18 +68,38 @@ func main() {
 	if err != nil {
 		log.Error("minio doesn't work")
 	}
+	bannedWords := cfg.Moderation.BannedWords
+	if cfg.Moderation.BannedWordsFile != "" {
+		words, err := service.ReadWordList(cfg.Moderation.BannedWordsFile)
+		if err != nil {
+			log.Fatal("cannot read banned words", zap.String("path", cfg.Moderation.BannedWordsFile), zap.Error(err))
+		}
+		bannedWords = append(bannedWords, words...)
+	}
+
 	rmq, err := rabbitmq.ConnectRabbitMQ(cfg.RabbitMq.Uri)
 	if err != nil {
 		panic(err)
 	}
 	defer rmq.Close()
 	svc := service.New(&service.Deps{
-		Repository: repo,
-		JwtMgr:     jwtMgr,
-		Hasher:     hasher.New(),
-		RabbitMq:   rmq,
-		Minio:      minio,
-		MailClient: mail.New(cfg.Smtp.Host, cfg.Smtp.Port, cfg.Smtp.Username, cfg.Smtp.Password, cfg.Smtp.Username),
+		Repository:     repo,
+		JwtMgr:         jwtMgr,
+		Hasher:         hasher.New(),
+		RabbitMq:       rmq,
+		Minio:          minio,
+		MailClient:     mail.New(cfg.Smtp.Host, cfg.Smtp.Port, cfg.Smtp.Username, cfg.Smtp.Password, cfg.Smtp.Username),
+		Location:       loc,
+		HoldTTL:        cfg.Booking.HoldTTL,
+		IdempotencyTTL: cfg.Booking.IdempotencyTTL,
+		ModeratorIDs:   cfg.Moderation.ModeratorIDs,
+		HideThreshold:  cfg.Moderation.HideThreshold,
+		Screening: service.ScreeningConfig{
+			BannedWords:    bannedWords,
+			DuplicateLimit: cfg.Moderation.DuplicateLimit,
+		},
+		SuggestTTL:               cfg.Search.SuggestTTL,
+		AllowPrivateCalendarURLs: cfg.Calendar.AllowPrivateURLs,
 	})
 
 	h := handlers.New(svc, jwtMgr)
@@ -0,0 +1,44 @@
+// Command recompute-ratings rebuilds the denormalized master_ratings table
+// from reviews. Review writes keep it up to date, so it is only needed after
+// manual data fixes or to verify the counters.
+package main
+
+import (
+	"context"
+	"strawberry/internal/config"
+	"strawberry/internal/repository"
+	"strawberry/pkg/logger"
+	db "strawberry/pkg/postgres"
+
+	"github.com/kelseyhightower/envconfig"
+	"go.uber.org/zap"
+)
+
+func main() {
+	ctx := logger.WithLogger(context.Background())
+	log := logger.FromContext(ctx)
+
+	var cfg config.Config
+	if err := envconfig.Process("", &cfg.Database); err != nil {
+		log.Fatal("cannot load database config", zap.Error(err))
+	}
+
+	pool, err := db.NewPGXPool(ctx, db.Config{
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		Name:     cfg.Database.Name,
+		SSLMode:  cfg.Database.SSLMode,
+	})
+	if err != nil {
+		log.Fatal("failed to connect to database", zap.Error(err))
+	}
+	defer pool.Close()
+
+	masters, err := repository.New(pool, nil).Reviews.RecomputeRatings(ctx)
+	if err != nil {
+		log.Fatal("failed to recompute ratings", zap.Error(err))
+	}
+	log.Info("ratings recomputed", zap.Int64("masters", masters))
+}
@@ -8,7 +8,8 @@ import (
 
 type Config struct {
 	App struct {
-		Port int `envconfig:"PORT" default:"8080"`
+		Port     int    `envconfig:"PORT" default:"8080"`
+		TimeZone string `envconfig:"APP_TIMEZONE" default:"Europe/Moscow"`
 	}
 	Database struct {
 		User     string `envconfig:"PGUSER" required:"true"`
@@ -46,6 +47,27 @@ type Config struct {
 	Verification struct {
 		TTL time.Duration `envconfig:"VERIFICATION_TTL" default:"10m"`
 	}
+	Booking struct {
+		HoldTTL        time.Duration `envconfig:"HOLD_TTL" default:"5m"`
+		IdempotencyTTL time.Duration `envconfig:"IDEMPOTENCY_TTL" default:"24h"`
+	}
+	Calendar struct {
+		// AllowPrivateURLs lets imported calendar urls point to loopback
+		// and private networks.
+		AllowPrivateURLs bool `envconfig:"CALENDAR_ALLOW_PRIVATE_URLS" default:"false"`
+	}
+	Moderation struct {
+		ModeratorIDs  []int64  `envconfig:"MODERATOR_IDS"`
+		HideThreshold int      `envconfig:"REVIEW_HIDE_THRESHOLD" default:"3"`
+		BannedWords   []string `envconfig:"REVIEW_BANNED_WORDS"`
+		// BannedWordsFile is a local word list, one word or phrase per
+		// line, added to BannedWords.
+		BannedWordsFile string `envconfig:"REVIEW_BANNED_WORDS_FILE"`
+		DuplicateLimit  int    `envconfig:"REVIEW_DUPLICATE_LIMIT" default:"3"`
+	}
+	Search struct {
+		SuggestTTL time.Duration `envconfig:"SUGGEST_CACHE_TTL" default:"5m"`
+	}
 }
 
 func MustLoad() Config {
@@ -12,22 +12,48 @@ import (
 )
 
 type AppointmentReq struct {
-	MasterID int64  `json:"master_id"`
-	Time     string `json:"time"`
+	MasterID   int64          `json:"master_id"`
+	Time       string         `json:"time"`
+	Recurrence *RecurrenceReq `json:"recurrence,omitempty"`
+	// IncludeOtherMasters adds slots of masters with the same specialization to alternatives on conflict.
+	IncludeOtherMasters bool `json:"include_other_masters,omitempty"`
+}
+
+type RecurrenceReq struct {
+	Frequency string `json:"frequency" example:"biweekly"`
+	Count     int    `json:"count,omitempty"`
+	Until     string `json:"until,omitempty" example:"2025-12-31"`
 }
 
 type AppointmentRes struct {
-	ID int64 `json:"id"`
+	ID       int64   `json:"id"`
+	SeriesID int64   `json:"series_id,omitempty"`
+	IDs      []int64 `json:"ids,omitempty"`
+}
+
+type SlotConflictResponse struct {
+	Error        string                   `json:"error"`
+	Alternatives []models.SlotAlternative `json:"alternatives"`
+}
+
+type OccurrenceConflictResponse struct {
+	Error     string                      `json:"error"`
+	Conflicts []models.OccurrenceConflict `json:"conflicts"`
 }
 
 // @Summary Create a new appointment
-// @Description Create a new appointment for the authenticated user
+// @Description Create a new appointment for the authenticated user.
+// @Description With recurrence set, every occurrence is checked up front and the whole series is created or nothing is
+// @Description (409 with the list of conflicting occurrences).
+// @Description When a single slot is taken or unavailable, 409 lists the nearest free alternatives (SlotConflictResponse).
 // @Tags appointments
 // @Accept json
 // @Produce json
 // @Param input body AppointmentReq true "appointment info"
+// @Param Idempotency-Key header string false "retries with the same key replay the first response"
 // @Success 201 {object} AppointmentRes
-// @Failure 409 {object} ErrorResponse
+// @Failure 409 {object} OccurrenceConflictResponse
+// @Failure 422 {object} ErrorResponse "idempotency key reused with a different body"
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -52,15 +78,28 @@ func (h *Handler) CreateAppointment(c *gin.Context) {
 		return
 	}
 
-	id, err := h.s.Appointments.Create(c.Request.Context(), &models.Appointment{
+	appointment := &models.Appointment{
 		UserID:      claims.Id,
 		MasterID:    data.MasterID,
 		ScheduledAt: scheduledAt,
-		Status:      "pending",
-	})
+	}
+	if data.Recurrence != nil {
+		h.createRecurringAppointment(c, appointment, data.Recurrence)
+		return
+	}
+
+	id, err := h.s.Appointments.Create(c.Request.Context(), appointment)
 	if err != nil {
 		if errors.Is(err, service.ErrMasterUnavaliable) {
-			newErrorResponse(http.StatusConflict, "master unavaliable", c)
+			h.slotConflict(c, "master unavaliable", appointment, data.IncludeOtherMasters)
+			return
+		}
+		if errors.Is(err, service.ErrAppointmentConflict) {
+			h.slotConflict(c, "appointment with this time already exists", appointment, data.IncludeOtherMasters)
+			return
+		}
+		if errors.Is(err, service.ErrSlotHeld) {
+			h.slotConflict(c, "slot is being booked by another client", appointment, data.IncludeOtherMasters)
 			return
 		}
 		var valErr service.ValidationError
@@ -75,6 +114,60 @@ func (h *Handler) CreateAppointment(c *gin.Context) {
 	c.JSON(http.StatusCreated, &AppointmentRes{ID: id})
 }
 
+// slotConflict responds with 409 and free alternatives. Failing to compute them
+// doesn't hide the conflict itself.
+func (h *Handler) slotConflict(c *gin.Context, msg string, a *models.Appointment, includeOthers bool) {
+	alternatives, err := h.s.Appointments.SuggestAlternatives(c.Request.Context(), a.UserID, a.MasterID, a.ScheduledAt, includeOthers)
+	if err != nil || alternatives == nil {
+		alternatives = []models.SlotAlternative{}
+	}
+	c.JSON(http.StatusConflict, &SlotConflictResponse{Error: msg, Alternatives: alternatives})
+}
+
+func (h *Handler) createRecurringAppointment(c *gin.Context, a *models.Appointment, data *RecurrenceReq) {
+	rule := &models.Recurrence{
+		Frequency: data.Frequency,
+		Count:     data.Count,
+	}
+	if data.Until != "" {
+		until, err := time.Parse("2006-01-02", data.Until)
+		if err != nil {
+			newErrorResponse(http.StatusBadRequest, "invalid until date", c)
+			return
+		}
+		rule.Until = &until
+	}
+
+	series, ids, err := h.s.Appointments.CreateRecurring(c.Request.Context(), a, rule)
+	if err != nil {
+		var conflictErr *service.OccurrenceConflictError
+		if errors.As(err, &conflictErr) {
+			c.JSON(http.StatusConflict, &OccurrenceConflictResponse{
+				Error:     conflictErr.Error(),
+				Conflicts: conflictErr.Conflicts,
+			})
+			return
+		}
+		if errors.Is(err, service.ErrAppointmentConflict) {
+			newErrorResponse(http.StatusConflict, "appointment with this time already exists", c)
+			return
+		}
+		var valErr service.ValidationError
+		if errors.As(err, &valErr) {
+			newErrorResponse(http.StatusBadRequest, valErr.Msg, c)
+			return
+		}
+		newErrorResponse(http.StatusInternalServerError, "could not create appointments", c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, &AppointmentRes{
+		ID:       ids[0],
+		SeriesID: series.ID,
+		IDs:      ids,
+	})
+}
+
 type Appointments struct {
 	MasterAppointments []models.Appointment `json:"master_appointments"`
 	UserAppointments   []models.Appointment `json:"user_appointments"`
@@ -119,14 +212,16 @@ func (h *Handler) GetAppointments(c *gin.Context) {
 	})
 }
 
-// @Summary Delete an appointment
-// @Description Delete an appointment by ID if it belongs to the user
+// @Summary Cancel an appointment
+// @Description Cancel an appointment by ID if it belongs to the user. Clients canceling after the master's free-cancel deadline get a late cancellation mark
 // @Tags appointments
 // @Accept json
 // @Produce json
 // @Param id path int true "Appointment ID"
 // @Success 204 {string} string "No Content"
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 409 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -148,13 +243,122 @@ func (h *Handler) DeleteAppointment(c *gin.Context) {
 
 	err = h.s.Appointments.Delete(c.Request.Context(), id, claims.Id)
 	if err != nil {
-		if errors.Is(err, service.ErrAppointmentConflict) {
-			newErrorResponse(http.StatusConflict, "appointment with this time already exists", c)
-			return
+		switch {
+		case errors.Is(err, service.ErrAppointmentNotFound):
+			newErrorResponse(http.StatusNotFound, "appointment not found", c)
+		case errors.Is(err, service.ErrUnauthorized):
+			newErrorResponse(http.StatusForbidden, "it's not your appointment", c)
+		case errors.Is(err, service.ErrAppointmentStarted):
+			newErrorResponse(http.StatusConflict, "appointment already started", c)
+		case errors.Is(err, service.ErrAppointmentCanceled):
+			newErrorResponse(http.StatusConflict, "appointment already canceled", c)
+		default:
+			newErrorResponse(http.StatusInternalServerError, "could not delete appointment", c)
 		}
-		newErrorResponse(http.StatusInternalServerError, "could not delete appointment", c)
 		return
 	}
 
 	c.JSON(http.StatusNoContent, nil)
 }
+
+// @Summary Cancel an appointment series
+// @Description Cancel all upcoming occurrences of a recurring appointment. Single occurrences are canceled with DELETE /appointments/{id}
+// @Tags appointments
+// @Produce json
+// @Param id path int true "Series ID"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /appointments/series/{id} [delete]
+func (h *Handler) CancelAppointmentSeries(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid ID", c)
+		return
+	}
+
+	claims, exists := getClaims(c)
+	if !exists {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	err = h.s.Appointments.CancelSeries(c.Request.Context(), id, claims.Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAppointmentNotFound):
+			newErrorResponse(http.StatusNotFound, "series not found", c)
+		case errors.Is(err, service.ErrUnauthorized):
+			newErrorResponse(http.StatusForbidden, "it's not your appointment", c)
+		case errors.Is(err, service.ErrAppointmentCanceled):
+			newErrorResponse(http.StatusConflict, "no upcoming appointments left in the series", c)
+		default:
+			newErrorResponse(http.StatusInternalServerError, "could not cancel series", c)
+		}
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+type UpdateAppointmentStatusReq struct {
+	Status string `json:"status" binding:"required"`
+}
+
+// @Summary Update appointment status
+// @Description Master confirms a pending appointment or marks it as completed or no_show once it started
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param id path int true "Appointment ID"
+// @Param input body UpdateAppointmentStatusReq true "new status"
+// @Success 200 {string} string "OK"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /appointments/{id}/status [put]
+func (h *Handler) UpdateAppointmentStatus(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid ID", c)
+		return
+	}
+
+	claims, exists := getClaims(c)
+	if !exists {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	var input UpdateAppointmentStatusReq
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid request", c)
+		return
+	}
+
+	err = h.s.Appointments.UpdateStatus(c.Request.Context(), id, claims.Id, input.Status)
+	if err != nil {
+		var valErr service.ValidationError
+		switch {
+		case errors.As(err, &valErr):
+			newErrorResponse(http.StatusBadRequest, valErr.Msg, c)
+		case errors.Is(err, service.ErrAppointmentNotFound):
+			newErrorResponse(http.StatusNotFound, "appointment not found", c)
+		case errors.Is(err, service.ErrUnauthorized):
+			newErrorResponse(http.StatusForbidden, "it's not your appointment", c)
+		default:
+			newErrorResponse(http.StatusInternalServerError, "could not update appointment", c)
+		}
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
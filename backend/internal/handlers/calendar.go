@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strawberry/internal/service"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CalendarTokenRes struct {
+	Token      string `json:"token"`
+	MasterFeed string `json:"master_feed"`
+	ClientFeed string `json:"client_feed"`
+}
+
+func newCalendarTokenRes(token string) *CalendarTokenRes {
+	return &CalendarTokenRes{
+		Token:      token,
+		MasterFeed: fmt.Sprintf("/api/calendar/master/%s.ics", token),
+		ClientFeed: fmt.Sprintf("/api/calendar/client/%s.ics", token),
+	}
+}
+
+// @Summary Get calendar feed token
+// @Description Returns the secret token and links of the user's .ics feeds, creating the token on first call
+// @Tags calendar
+// @Produce json
+// @Success 200 {object} CalendarTokenRes
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /calendar/token [get]
+func (h *Handler) GetCalendarToken(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	token, err := h.s.Calendar.GetFeedToken(c.Request.Context(), claims.Id)
+	if err != nil {
+		newErrorResponse(http.StatusInternalServerError, "cannot get calendar token", c)
+		return
+	}
+	c.JSON(http.StatusOK, newCalendarTokenRes(token))
+}
+
+// @Summary Rotate calendar feed token
+// @Description Issues a new feed token. Links with the old token stop working
+// @Tags calendar
+// @Produce json
+// @Success 200 {object} CalendarTokenRes
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /calendar/token [post]
+func (h *Handler) RotateCalendarToken(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	token, err := h.s.Calendar.RotateFeedToken(c.Request.Context(), claims.Id)
+	if err != nil {
+		newErrorResponse(http.StatusInternalServerError, "cannot rotate calendar token", c)
+		return
+	}
+	c.JSON(http.StatusOK, newCalendarTokenRes(token))
+}
+
+// @Summary Master's calendar feed
+// @Description iCalendar feed of appointments booked with the token owner
+// @Tags calendar
+// @Produce text/calendar
+// @Param token path string true "Feed token, optionally with .ics suffix"
+// @Success 200 {file} file
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /calendar/master/{token} [get]
+func (h *Handler) GetMasterCalendarFeed(c *gin.Context) {
+	h.calendarFeed(c, true)
+}
+
+// @Summary Client's calendar feed
+// @Description iCalendar feed of appointments booked by the token owner
+// @Tags calendar
+// @Produce text/calendar
+// @Param token path string true "Feed token, optionally with .ics suffix"
+// @Success 200 {file} file
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /calendar/client/{token} [get]
+func (h *Handler) GetClientCalendarFeed(c *gin.Context) {
+	h.calendarFeed(c, false)
+}
+
+func (h *Handler) calendarFeed(c *gin.Context, asMaster bool) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	feed, err := h.s.Calendar.Feed(c.Request.Context(), token, asMaster)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidFeedToken) {
+			newErrorResponse(http.StatusNotFound, "feed not found", c)
+			return
+		}
+		newErrorResponse(http.StatusInternalServerError, "cannot build calendar", c)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", feed)
+}
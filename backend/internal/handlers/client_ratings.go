@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strawberry/internal/models"
+	"strawberry/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RateClientReq struct {
+	Score int    `json:"score" binding:"required" example:"5"`
+	Note  string `json:"note"`
+}
+
+// @Summary Rate the client of an appointment
+// @Description Saves the master's private score and note about the client of a completed appointment. Rating the same appointment again replaces the rating. Masters only
+// @Tags policy
+// @Accept json
+// @Produce json
+// @Param id path int true "Appointment ID"
+// @Param input body RateClientReq true "rating"
+// @Success 200 {object} models.ClientRating
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /masters/appointments/{id}/client-rating [put]
+func (h *Handler) RateClient(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	aptId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid id", c)
+		return
+	}
+
+	var input RateClientReq
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(http.StatusBadRequest, "bad data", c)
+		return
+	}
+
+	rating := &models.ClientRating{
+		AppointmentId: aptId,
+		MasterId:      claims.Id,
+		Score:         input.Score,
+		Note:          input.Note,
+	}
+	if err := h.s.Appointments.RateClient(c.Request.Context(), rating); err != nil {
+		clientRatingErrorResponse(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, rating)
+}
+
+// @Summary Get client's reliability
+// @Description Returns the ratings masters gave the client and a reliability indicator built from them and the client's visit statistics. Masters only, for clients who booked with them
+// @Tags policy
+// @Produce json
+// @Param id path int true "Client ID"
+// @Success 200 {object} models.ClientReliability
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /masters/clients/{id}/reliability [get]
+func (h *Handler) GetClientReliability(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	clientId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid id", c)
+		return
+	}
+
+	rel, err := h.s.Appointments.ClientReliability(c.Request.Context(), claims.Id, clientId)
+	if err != nil {
+		clientRatingErrorResponse(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, rel)
+}
+
+func clientRatingErrorResponse(err error, c *gin.Context) {
+	var valErr service.ValidationError
+	switch {
+	case errors.As(err, &valErr):
+		newErrorResponse(http.StatusBadRequest, valErr.Msg, c)
+	case errors.Is(err, service.ErrAppointmentNotFound):
+		newErrorResponse(http.StatusNotFound, "appointment not found", c)
+	case errors.Is(err, service.ErrNotMaster), errors.Is(err, service.ErrNotAppointmentMaster),
+		errors.Is(err, service.ErrClientNotRateable), errors.Is(err, service.ErrNotYourClient):
+		newErrorResponse(http.StatusForbidden, err.Error(), c)
+	default:
+		newErrorResponse(http.StatusInternalServerError, "cannot process client rating", c)
+	}
+}
@@ -32,6 +32,7 @@ func (h *Handler) InitRoutes() *gin.Engine {
 	{
 
 		api.GET("/search", h.Search)
+		api.GET("/search/suggest", h.Suggest)
 
 		api.POST("/send-code", h.SendVerificationCode)
 
@@ -47,33 +48,76 @@ func (h *Handler) InitRoutes() *gin.Engine {
 		api.GET("/users/:id/works/:workId", h.GetMasterWork)
 
 		api.GET("/users/:id/avatar", h.GetAvatar)
+		api.GET("/users/:id/policy", h.GetCancellationPolicy)
 		api.GET("/reviews/master/:master_id", h.GetReviewsByMasterId)
+		api.GET("/reviews/:id/photos/:photoId", h.GetReviewPhoto)
+		api.GET("/reviews/:id/revisions", h.GetReviewRevisions)
 
 		api.GET("schedule/:id", h.GetSchedule)
+
+		api.GET("/calendar/master/:token", h.GetMasterCalendarFeed)
+		api.GET("/calendar/client/:token", h.GetClientCalendarFeed)
 		auth := api.Group("/")
 		auth.Use(h.authMiddleware())
 		{
 			reviews := auth.Group("/reviews")
 			{
-				reviews.POST("/", h.CreateReview)
+				reviews.POST("/", h.idempotencyMiddleware(), h.CreateReview)
+				reviews.GET("/pending", h.GetAwaitingReview)
 				reviews.PUT("/:id", h.UpdateReview)
 				reviews.DELETE("/:id", h.DeleteReview)
+				reviews.POST("/:id/reply", h.CreateReviewReply)
+				reviews.PUT("/:id/reply", h.UpdateReviewReply)
+				reviews.DELETE("/:id/reply", h.DeleteReviewReply)
+				reviews.POST("/:id/photos", h.AddReviewPhoto)
+				reviews.DELETE("/:id/photos/:photoId", h.DeleteReviewPhoto)
+				reviews.POST("/:id/report", h.ReportReview)
+				reviews.PUT("/:id/vote", h.VoteReview)
+				reviews.DELETE("/:id/vote", h.DeleteReviewVote)
 			}
 			auth.PUT("/users", h.UpdateUser)
 			auth.GET("/masters/appointments", h.GetMasterAppointments)
+			auth.GET("/masters/appointments/history", h.GetMasterAppointmentHistory)
+			auth.PUT("/masters/policy", h.SetCancellationPolicy)
+			auth.GET("/masters/clients/:id/stats", h.GetClientStats)
+			auth.GET("/masters/clients/:id/reliability", h.GetClientReliability)
+			auth.PUT("/masters/appointments/:id/client-rating", h.RateClient)
 			auth.POST("users/works", h.UploadMasterWork)
 			auth.POST("/users/avatar", h.UploadAvatar)
 			auth.DELETE("masters/works/:id", h.DeleteMasterWork)
+			auth.GET("/moderation/reviews", h.GetModerationQueue)
+			auth.PUT("/moderation/reviews/:id", h.ResolveReviewReports)
 			auth.GET("/appointments", h.GetAppointments)
-			auth.POST("/appointments", h.CreateAppointment)
+			auth.GET("/appointments/history", h.GetAppointmentHistory)
+			auth.POST("/appointments", h.idempotencyMiddleware(), h.CreateAppointment)
 			auth.DELETE("/appointments/:id", h.DeleteAppointment)
+			auth.PUT("/appointments/:id/status", h.UpdateAppointmentStatus)
+			auth.DELETE("/appointments/series/:id", h.CancelAppointmentSeries)
+			auth.POST("/appointments/hold", h.HoldSlot)
+			auth.DELETE("/appointments/hold", h.ReleaseSlot)
+
+			auth.GET("/calendar/token", h.GetCalendarToken)
+			auth.POST("/calendar/token", h.RotateCalendarToken)
 
 			auth.PUT("/schedule/dayoff", h.SetDayOff)
 
 			auth.PUT("/schedule/hours/weekday", h.SetWorkingSlotsByWeekDay)
 
+			auth.GET("/schedule/templates", h.GetScheduleTemplates)
+			auth.PUT("/schedule/templates", h.SetScheduleTemplate)
+			auth.DELETE("/schedule/templates/:date", h.DeleteScheduleTemplate)
+			auth.PUT("/schedule/week", h.SetWeekSchedule)
+
 			auth.PUT("/schedule/hours/date", h.SetWorkingSlotsByDate)
 			auth.DELETE("/schedule/hours/date", h.DeleteWorkingSlotsByDate)
+
+			auth.POST("/schedule/import", h.ImportCalendar)
+			auth.PUT("/schedule/import/url", h.SetCalendarURL)
+			auth.POST("/schedule/import/refresh", h.RefreshCalendar)
+
+			auth.GET("/schedule/blocks", h.GetTimeBlocks)
+			auth.POST("/schedule/blocks", h.CreateTimeBlock)
+			auth.DELETE("/schedule/blocks/:id", h.DeleteTimeBlock)
 		}
 	}
 	return r
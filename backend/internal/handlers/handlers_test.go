@@ -3,12 +3,14 @@ package handlers_test
 import (
 	"bytes"
 	"encoding/json"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strawberry/internal/handlers"
 	"strawberry/internal/models"
 	"strawberry/internal/service"
 	mock_service "strawberry/internal/service/mocks"
+	"strawberry/pkg/jwt"
 	mock_jwt "strawberry/pkg/jwt/mocks"
 	"testing"
 	"time"
@@ -23,11 +25,15 @@ const userCtxKey = "userId"
 func setup() (*handlers.Handler, *mock_service.Users, *mock_service.Appointments) {
 	userMock := new(mock_service.Users)
 	apptMock := new(mock_service.Appointments)
+	codeMock := new(mock_service.VerificationCode)
 	jwtMock := new(mock_jwt.JwtManager)
 
+	codeMock.On("VerifyCode", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
 	svc := &service.Service{
-		Users:        userMock,
-		Appointments: apptMock,
+		Users:            userMock,
+		Appointments:     apptMock,
+		VerificationCode: codeMock,
 	}
 	h := handlers.New(svc, jwtMock)
 	return h, userMock, apptMock
@@ -112,7 +118,7 @@ func TestCreateAppointment_Success(t *testing.T) {
 
 	input := handlers.AppointmentReq{
 		MasterID: masterID,
-		Time:     "2025-05-28",
+		Time:     "2025-05-28 00:00",
 	}
 
 	apptMock.On("Create", mock.Anything, mock.MatchedBy(func(a *models.Appointment) bool {
@@ -127,7 +133,7 @@ func TestCreateAppointment_Success(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
-	c.Set(userCtxKey, userID)
+	c.Set(userCtxKey, &jwt.CustomClaims{Id: userID})
 
 	h.CreateAppointment(c)
 
@@ -149,12 +155,13 @@ func TestGetAppointments_Success(t *testing.T) {
 		},
 	}
 	apptMock.On("GetByUserId", mock.Anything, userID).Return(appointments, nil)
+	apptMock.On("GetByMasterId", mock.Anything, userID).Return([]models.Appointment{}, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/appointments", nil)
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
-	c.Set("userId", userID)
+	c.Set(userCtxKey, &jwt.CustomClaims{Id: userID})
 
 	h.GetAppointments(c)
 
@@ -175,10 +182,580 @@ func TestDeleteAppointment_Success(t *testing.T) {
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
 	c.Params = gin.Params{{Key: "id", Value: "10"}}
-	c.Set(userCtxKey, userID)
+	c.Set(userCtxKey, &jwt.CustomClaims{Id: userID})
 
 	h.DeleteAppointment(c)
 
 	require.Equal(t, http.StatusNoContent, w.Code)
 	apptMock.AssertExpectations(t)
 }
+
+func TestDeleteAppointment_Started(t *testing.T) {
+	h, _, apptMock := setup()
+
+	userID := int64(1)
+	apptMock.On("Delete", mock.Anything, int64(10), userID).Return(service.ErrAppointmentStarted)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/appointments/10", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "10"}}
+	c.Set(userCtxKey, &jwt.CustomClaims{Id: userID})
+
+	h.DeleteAppointment(c)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+	apptMock.AssertExpectations(t)
+}
+
+func TestUpdateAppointmentStatus_Invalid(t *testing.T) {
+	h, _, apptMock := setup()
+
+	masterID := int64(2)
+	apptMock.On("UpdateStatus", mock.Anything, int64(10), masterID, "completed").
+		Return(service.ValidationError{Msg: "appointment has not started yet"})
+
+	body, _ := json.Marshal(handlers.UpdateAppointmentStatusReq{Status: "completed"})
+	req := httptest.NewRequest(http.MethodPut, "/api/appointments/10/status", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "10"}}
+	c.Set(userCtxKey, &jwt.CustomClaims{Id: masterID})
+
+	h.UpdateAppointmentStatus(c)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	apptMock.AssertExpectations(t)
+}
+
+func TestGetClientStats_NotMaster(t *testing.T) {
+	h, _, apptMock := setup()
+
+	userID := int64(3)
+	apptMock.On("GetClientStats", mock.Anything, userID, int64(7)).Return(nil, service.ErrNotMaster)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/masters/clients/7/stats", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	c.Set(userCtxKey, &jwt.CustomClaims{Id: userID})
+
+	h.GetClientStats(c)
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+	apptMock.AssertExpectations(t)
+}
+
+func TestCreateAppointment_RecurringConflict(t *testing.T) {
+	h, _, apptMock := setup()
+
+	userID := int64(1)
+	conflictAt := time.Date(2025, 6, 11, 10, 0, 0, 0, time.UTC)
+
+	apptMock.On("CreateRecurring", mock.Anything, mock.AnythingOfType("*models.Appointment"),
+		mock.MatchedBy(func(r *models.Recurrence) bool {
+			return r.Frequency == models.FrequencyBiweekly && r.Count == 4
+		})).
+		Return(nil, nil, &service.OccurrenceConflictError{
+			Conflicts: []models.OccurrenceConflict{{Time: conflictAt, Reason: "master unavaliable"}},
+		})
+
+	body, _ := json.Marshal(handlers.AppointmentReq{
+		MasterID:   2,
+		Time:       "2025-05-28 10:00",
+		Recurrence: &handlers.RecurrenceReq{Frequency: models.FrequencyBiweekly, Count: 4},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/appointments", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set(userCtxKey, &jwt.CustomClaims{Id: userID})
+
+	h.CreateAppointment(c)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+	var resp handlers.OccurrenceConflictResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Conflicts, 1)
+	require.True(t, resp.Conflicts[0].Time.Equal(conflictAt))
+	apptMock.AssertExpectations(t)
+}
+
+func TestHoldSlot_HeldByOther(t *testing.T) {
+	h, _, apptMock := setup()
+
+	apptMock.On("HoldSlot", mock.Anything, mock.MatchedBy(func(hold *models.SlotHold) bool {
+		return hold.UserID == 1 && hold.MasterID == 2 &&
+			hold.Time.Equal(time.Date(2025, 6, 2, 14, 0, 0, 0, time.UTC))
+	})).Return(service.ErrSlotHeld)
+
+	body, _ := json.Marshal(handlers.HoldSlotReq{MasterID: 2, Time: "2025-06-02 14:00"})
+	req := httptest.NewRequest(http.MethodPost, "/api/appointments/hold", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set(userCtxKey, &jwt.CustomClaims{Id: 1})
+
+	h.HoldSlot(c)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+	apptMock.AssertExpectations(t)
+}
+
+func setupIdempotentRouter() (*gin.Engine, *mock_service.Appointments, *mock_service.Idempotency) {
+	apptMock := new(mock_service.Appointments)
+	idemMock := new(mock_service.Idempotency)
+	jwtMock := new(mock_jwt.JwtManager)
+	jwtMock.On("Verify", "token").Return(&jwt.CustomClaims{Id: 1}, nil)
+
+	h := handlers.New(&service.Service{Appointments: apptMock, Idempotency: idemMock}, jwtMock)
+	return h.InitRoutes(), apptMock, idemMock
+}
+
+func newIdempotentRequest(body []byte) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/appointments", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("Idempotency-Key", "retry-1")
+	return req
+}
+
+func TestCreateAppointment_IdempotentFirstRequest(t *testing.T) {
+	r, apptMock, idemMock := setupIdempotentRouter()
+
+	idemMock.On("Begin", mock.Anything, int64(1), "retry-1", mock.AnythingOfType("string")).Return(nil, nil)
+	apptMock.On("Create", mock.Anything, mock.AnythingOfType("*models.Appointment")).Return(int64(55), nil)
+	idemMock.On("Complete", mock.Anything, int64(1), "retry-1", mock.MatchedBy(func(rec *models.IdempotencyRecord) bool {
+		return rec.Status == http.StatusCreated && bytes.Contains(rec.Body, []byte(`"id":55`))
+	})).Return(nil)
+
+	body, _ := json.Marshal(handlers.AppointmentReq{MasterID: 2, Time: "2025-05-28 10:00"})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newIdempotentRequest(body))
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	apptMock.AssertExpectations(t)
+	idemMock.AssertExpectations(t)
+}
+
+func TestCreateAppointment_IdempotentReplay(t *testing.T) {
+	r, apptMock, idemMock := setupIdempotentRouter()
+
+	idemMock.On("Begin", mock.Anything, int64(1), "retry-1", mock.AnythingOfType("string")).Return(&models.IdempotencyRecord{
+		Completed:   true,
+		Status:      http.StatusCreated,
+		ContentType: "application/json; charset=utf-8",
+		Body:        []byte(`{"id":55}`),
+	}, nil)
+
+	body, _ := json.Marshal(handlers.AppointmentReq{MasterID: 2, Time: "2025-05-28 10:00"})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newIdempotentRequest(body))
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.JSONEq(t, `{"id":55}`, w.Body.String())
+	require.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+	apptMock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateAppointment_IdempotencyKeyReused(t *testing.T) {
+	r, apptMock, idemMock := setupIdempotentRouter()
+
+	idemMock.On("Begin", mock.Anything, int64(1), "retry-1", mock.AnythingOfType("string")).
+		Return(nil, service.ErrIdempotencyMismatch)
+
+	body, _ := json.Marshal(handlers.AppointmentReq{MasterID: 3, Time: "2025-05-28 11:00"})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newIdempotentRequest(body))
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	apptMock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateAppointment_IdempotencyReleasedOnPanic(t *testing.T) {
+	r, apptMock, idemMock := setupIdempotentRouter()
+
+	idemMock.On("Begin", mock.Anything, int64(1), "retry-1", mock.AnythingOfType("string")).Return(nil, nil)
+	apptMock.On("Create", mock.Anything, mock.AnythingOfType("*models.Appointment")).
+		Run(func(mock.Arguments) { panic("boom") })
+	idemMock.On("Abort", mock.Anything, int64(1), "retry-1").Return(nil)
+
+	body, _ := json.Marshal(handlers.AppointmentReq{MasterID: 2, Time: "2025-05-28 10:00"})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newIdempotentRequest(body))
+
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	idemMock.AssertExpectations(t)
+	idemMock.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateAppointment_ConflictAlternatives(t *testing.T) {
+	h, _, apptMock := setup()
+
+	at := time.Date(2025, 6, 2, 14, 0, 0, 0, time.UTC)
+	apptMock.On("Create", mock.Anything, mock.AnythingOfType("*models.Appointment")).Return(int64(0), service.ErrAppointmentConflict)
+	apptMock.On("SuggestAlternatives", mock.Anything, int64(1), int64(2), at, true).Return([]models.SlotAlternative{
+		{MasterID: 2, Time: "2025-06-02 15:00"},
+		{MasterID: 7, MasterName: "Bob", Time: "2025-06-02 14:00"},
+	}, nil)
+
+	body, _ := json.Marshal(handlers.AppointmentReq{MasterID: 2, Time: "2025-06-02 14:00", IncludeOtherMasters: true})
+	req := httptest.NewRequest(http.MethodPost, "/api/appointments", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set(userCtxKey, &jwt.CustomClaims{Id: 1})
+
+	h.CreateAppointment(c)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+	var res handlers.SlotConflictResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	require.Len(t, res.Alternatives, 2)
+	require.Equal(t, "2025-06-02 15:00", res.Alternatives[0].Time)
+	apptMock.AssertExpectations(t)
+}
+
+func TestGetMasterAppointmentHistory_Filters(t *testing.T) {
+	h, _, apptMock := setup()
+
+	from := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	apptMock.On("History", mock.Anything, mock.MatchedBy(func(f models.HistoryFilter) bool {
+		return f.Role == models.HistoryRoleMaster && f.UserID == 2 &&
+			f.From != nil && f.From.Equal(from) && f.To == nil &&
+			len(f.Statuses) == 2 && f.Statuses[0] == "completed" && f.Statuses[1] == "no_show" &&
+			f.CounterpartID == 7 && f.Limit == 10 && f.Cursor == "abc"
+	})).Return(&models.HistoryPage{
+		Appointments: []models.Appointment{{ID: 3, UserID: 7, MasterID: 2, Status: "completed"}},
+		NextCursor:   "next",
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/masters/appointments/history?from=2025-05-01&status=completed,no_show&client_id=7&limit=10&cursor=abc", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set(userCtxKey, &jwt.CustomClaims{Id: 2})
+
+	h.GetMasterAppointmentHistory(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "next", w.Header().Get("X-Next-Cursor"))
+	var apts []models.Appointment
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &apts))
+	require.Len(t, apts, 1)
+	apptMock.AssertExpectations(t)
+}
+
+func TestGetAppointmentHistory_InvalidDate(t *testing.T) {
+	h, _, _ := setup()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/appointments/history?to=01.05.2025", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set(userCtxKey, &jwt.CustomClaims{Id: 1})
+
+	h.GetAppointmentHistory(c)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func setupReviews() (*handlers.Handler, *mock_service.Reviews) {
+	reviewsMock := new(mock_service.Reviews)
+	h := handlers.New(&service.Service{Reviews: reviewsMock}, new(mock_jwt.JwtManager))
+	return h, reviewsMock
+}
+
+func TestCreateReview_AlreadyReviewed(t *testing.T) {
+	h, reviewsMock := setupReviews()
+
+	reviewsMock.On("Create", mock.Anything, mock.MatchedBy(func(r *models.Review) bool {
+		return r.UserId == 1 && r.AppointmentId != nil && *r.AppointmentId == 5
+	})).Return(service.ErrAlreadyReviewed)
+
+	body, _ := json.Marshal(handlers.CreateReviewReq{AppointmentId: 5, MasterId: 2, Rating: 5})
+	req := httptest.NewRequest(http.MethodPost, "/api/reviews/", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set(userCtxKey, &jwt.CustomClaims{Id: 1})
+
+	h.CreateReview(c)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+	reviewsMock.AssertExpectations(t)
+}
+
+func TestCreateReview_NotCompleted(t *testing.T) {
+	h, reviewsMock := setupReviews()
+
+	reviewsMock.On("Create", mock.Anything, mock.Anything).Return(service.ErrAppointmentNotCompleted)
+
+	body, _ := json.Marshal(handlers.CreateReviewReq{AppointmentId: 5, Rating: 4})
+	req := httptest.NewRequest(http.MethodPost, "/api/reviews/", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set(userCtxKey, &jwt.CustomClaims{Id: 1})
+
+	h.CreateReview(c)
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestCreateReviewReply_NotReviewedMaster(t *testing.T) {
+	h, reviewsMock := setupReviews()
+
+	reviewsMock.On("CreateReply", mock.Anything, mock.MatchedBy(func(r *models.ReviewReply) bool {
+		return r.ReviewId == 9 && r.MasterId == 3 && r.Text == "Спасибо!"
+	})).Return(service.ErrNotReviewedMaster)
+
+	body, _ := json.Marshal(handlers.ReviewReplyReq{Text: "Спасибо!"})
+	req := httptest.NewRequest(http.MethodPost, "/api/reviews/9/reply", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "9"}}
+	c.Set(userCtxKey, &jwt.CustomClaims{Id: 3})
+
+	h.CreateReviewReply(c)
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+	reviewsMock.AssertExpectations(t)
+}
+
+func TestAddReviewPhoto_LimitReached(t *testing.T) {
+	h, reviewsMock := setupReviews()
+
+	reviewsMock.On("AddPhoto", mock.Anything, int64(1), int64(4), mock.Anything, int64(8)).Return(nil, service.ErrTooManyPhotos)
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fw, _ := mw.CreateFormFile("photo", "result.png")
+	fw.Write([]byte("\x89PNG\r\n\x1a\n"))
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/reviews/4/photos", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "4"}}
+	c.Set(userCtxKey, &jwt.CustomClaims{Id: 1})
+
+	h.AddReviewPhoto(c)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+	reviewsMock.AssertExpectations(t)
+}
+
+func TestReportReview_AlreadyReported(t *testing.T) {
+	h, reviewsMock := setupReviews()
+
+	reviewsMock.On("Report", mock.Anything, mock.MatchedBy(func(r *models.ReviewReport) bool {
+		return r.ReviewId == 6 && r.ReporterId == 2 && r.Reason == "spam"
+	})).Return(service.ErrAlreadyReported)
+
+	body, _ := json.Marshal(handlers.ReportReviewReq{Reason: "spam"})
+	req := httptest.NewRequest(http.MethodPost, "/api/reviews/6/report", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "6"}}
+	c.Set(userCtxKey, &jwt.CustomClaims{Id: 2})
+
+	h.ReportReview(c)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+	reviewsMock.AssertExpectations(t)
+}
+
+func TestGetReviewsByMasterId_Filters(t *testing.T) {
+	h, reviewsMock := setupReviews()
+
+	reviewsMock.On("GetByMasterId", mock.Anything, models.ReviewFilter{
+		MasterID:   5,
+		Ratings:    []int{4, 5},
+		WithPhotos: true,
+		Sort:       models.ReviewSortHighest,
+		Limit:      10,
+	}).Return(&models.ReviewPage{Reviews: []models.Review{{Id: 1, MasterId: 5, Rating: 5}}, NextCursor: "next"}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/reviews/master/5?sort=highest&rating=4,5&with_photos=true&limit=10", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "master_id", Value: "5"}}
+
+	h.GetReviewsByMasterId(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "next", w.Header().Get("X-Next-Cursor"))
+	reviewsMock.AssertExpectations(t)
+}
+
+func TestVoteReview_OwnReview(t *testing.T) {
+	h, reviewsMock := setupReviews()
+
+	reviewsMock.On("Vote", mock.Anything, &models.ReviewVote{ReviewId: 7, UserId: 2, Helpful: false}).
+		Return(service.ErrVoteOwnReview)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/reviews/7/vote", bytes.NewBufferString(`{"helpful": false}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	c.Set(userCtxKey, &jwt.CustomClaims{Id: 2})
+
+	h.VoteReview(c)
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+	reviewsMock.AssertExpectations(t)
+}
+
+func TestCreateReview_RejectedByScreening(t *testing.T) {
+	h, reviewsMock := setupReviews()
+
+	reviewsMock.On("Create", mock.Anything, mock.AnythingOfType("*models.Review")).
+		Return(service.ReviewRejectedError{Reasons: []string{"contains a prohibited word"}})
+
+	body, _ := json.Marshal(handlers.CreateReviewReq{AppointmentId: 11, Rating: 1, Comment: "..."})
+	req := httptest.NewRequest(http.MethodPost, "/api/reviews", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set(userCtxKey, &jwt.CustomClaims{Id: 1})
+
+	h.CreateReview(c)
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	require.Contains(t, w.Body.String(), "contains a prohibited word")
+	reviewsMock.AssertExpectations(t)
+}
+
+func TestGetClientReliability_NotYourClient(t *testing.T) {
+	h, _, apptMock := setup()
+
+	apptMock.On("ClientReliability", mock.Anything, int64(3), int64(7)).Return(nil, service.ErrNotYourClient)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/masters/clients/7/reliability", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	c.Set(userCtxKey, &jwt.CustomClaims{Id: 3})
+
+	h.GetClientReliability(c)
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+	apptMock.AssertExpectations(t)
+}
+
+func TestGetReviewRevisions(t *testing.T) {
+	h, reviewsMock := setupReviews()
+
+	written := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	reviewsMock.On("Revisions", mock.Anything, int64(4)).Return([]models.ReviewRevision{
+		{Id: 1, ReviewId: 4, Rating: 5, Comment: "Отлично", WrittenAt: written, ReplacedAt: written.Add(48 * time.Hour)},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/reviews/4/revisions", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "4"}}
+
+	h.GetReviewRevisions(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var revisions []models.ReviewRevision
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &revisions))
+	require.Len(t, revisions, 1)
+	require.Equal(t, 5, revisions[0].Rating)
+	reviewsMock.AssertExpectations(t)
+}
+
+func TestSearch_Paginated(t *testing.T) {
+	h, usersMock, _ := setup()
+
+	usersMock.On("Search", mock.Anything, models.SearchQuery{Text: "маникюр", Limit: 2, Cursor: "c1"}).
+		Return(&models.SearchPage{Masters: []models.User{{Id: 3}, {Id: 5}}, NextCursor: "c2"}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search?key=маникюр&limit=2&cursor=c1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Search(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "c2", w.Header().Get("X-Next-Cursor"))
+	usersMock.AssertExpectations(t)
+}
+
+func TestGetMasters_Filtered(t *testing.T) {
+	h, usersMock, _ := setup()
+
+	freeOn := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	f := models.MasterFilter{
+		Specialization: "nails",
+		MinRating:      4.5,
+		FreeOn:         &freeOn,
+		Sort:           models.MasterSortReviews,
+		Limit:          2,
+		Offset:         4,
+	}
+	usersMock.On("FindMasters", mock.Anything, f).
+		Return(&models.MasterPage{Masters: []models.User{{Id: 7}, {Id: 9}}, Total: 11}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/masters?specialization=nails&min_rating=4.5&free_on=2025-06-02&sort=reviews&limit=2&offset=4", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.GetMasters(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "11", w.Header().Get("X-Total-Count"))
+	var masters []models.User
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &masters))
+	require.Len(t, masters, 2)
+	usersMock.AssertExpectations(t)
+}
+
+func TestSuggest(t *testing.T) {
+	h, usersMock, _ := setup()
+
+	usersMock.On("Suggest", mock.Anything, models.SuggestQuery{Prefix: "ман", Limit: 5}).
+		Return([]models.Suggestion{
+			{Kind: models.SuggestionKindSpecialization, Text: "маникюр"},
+			{Kind: models.SuggestionKindMaster, Text: "Мария Манина", Username: "manina"},
+		}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search/suggest?q=ман&limit=5", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Suggest(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var suggestions []models.Suggestion
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &suggestions))
+	require.Len(t, suggestions, 2)
+	require.Equal(t, "manina", suggestions[1].Username)
+	usersMock.AssertExpectations(t)
+}
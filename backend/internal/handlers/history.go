@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strawberry/internal/models"
+	"strawberry/internal/service"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get appointment history of the client
+// @Description Lists the current user's appointments as a client in any status, past ones included. The next page cursor is returned in the X-Next-Cursor header
+// @Tags appointments
+// @Produce json
+// @Param from query string false "YYYY-MM-DD"
+// @Param to query string false "YYYY-MM-DD, inclusive"
+// @Param status query []string false "statuses, repeated or comma separated" collectionFormat(multi)
+// @Param master_id query int false "only appointments with this master"
+// @Param sort query string false "asc or desc by time, desc by default"
+// @Param limit query int false "page size, 20 by default, at most 100"
+// @Param cursor query string false "X-Next-Cursor of the previous page"
+// @Success 200 {array} models.Appointment
+// @Header 200 {string} X-Next-Cursor "cursor of the next page, absent on the last one"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /appointments/history [get]
+func (h *Handler) GetAppointmentHistory(c *gin.Context) {
+	h.appointmentHistory(c, models.HistoryRoleClient, "master_id")
+}
+
+// @Summary Get appointment history of the master
+// @Description Lists appointments booked with the current master in any status, past ones included. The next page cursor is returned in the X-Next-Cursor header
+// @Tags appointments
+// @Produce json
+// @Param from query string false "YYYY-MM-DD"
+// @Param to query string false "YYYY-MM-DD, inclusive"
+// @Param status query []string false "statuses, repeated or comma separated" collectionFormat(multi)
+// @Param client_id query int false "only appointments of this client"
+// @Param sort query string false "asc or desc by time, desc by default"
+// @Param limit query int false "page size, 20 by default, at most 100"
+// @Param cursor query string false "X-Next-Cursor of the previous page"
+// @Success 200 {array} models.Appointment
+// @Header 200 {string} X-Next-Cursor "cursor of the next page, absent on the last one"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /masters/appointments/history [get]
+func (h *Handler) GetMasterAppointmentHistory(c *gin.Context) {
+	h.appointmentHistory(c, models.HistoryRoleMaster, "client_id")
+}
+
+func (h *Handler) appointmentHistory(c *gin.Context, role string, counterpartParam string) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	f := models.HistoryFilter{
+		Role:   role,
+		UserID: claims.Id,
+		Sort:   c.Query("sort"),
+		Cursor: c.Query("cursor"),
+	}
+	for _, p := range []struct {
+		name string
+		dst  **time.Time
+	}{{"from", &f.From}, {"to", &f.To}} {
+		if v := c.Query(p.name); v != "" {
+			d, err := time.Parse("2006-01-02", v)
+			if err != nil {
+				newErrorResponse(http.StatusBadRequest, "invalid "+p.name+", expected YYYY-MM-DD", c)
+				return
+			}
+			*p.dst = &d
+		}
+	}
+	for _, v := range c.QueryArray("status") {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				f.Statuses = append(f.Statuses, s)
+			}
+		}
+	}
+	if v := c.Query(counterpartParam); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			newErrorResponse(http.StatusBadRequest, "invalid "+counterpartParam, c)
+			return
+		}
+		f.CounterpartID = id
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			newErrorResponse(http.StatusBadRequest, "invalid limit", c)
+			return
+		}
+		f.Limit = limit
+	}
+
+	page, err := h.s.Appointments.History(c.Request.Context(), f)
+	if err != nil {
+		var valErr service.ValidationError
+		if errors.As(err, &valErr) {
+			newErrorResponse(http.StatusBadRequest, valErr.Msg, c)
+			return
+		}
+		newErrorResponse(http.StatusInternalServerError, "failed to get appointment history", c)
+		return
+	}
+	if page.NextCursor != "" {
+		c.Header("X-Next-Cursor", page.NextCursor)
+	}
+	c.JSON(http.StatusOK, page.Appointments)
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strawberry/internal/models"
+	"strawberry/internal/service"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HoldSlotReq struct {
+	MasterID int64  `json:"master_id" binding:"required"`
+	Time     string `json:"time" binding:"required" example:"2025-06-02 14:00"`
+}
+
+// @Summary Hold slot while booking
+// @Description Reserves an available slot for the current client for a short time. Others can't book or hold it until the hold expires or turns into an appointment on POST /appointments. A new hold releases the client's previous one
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param input body HoldSlotReq true "slot"
+// @Success 200 {object} models.SlotHold
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /appointments/hold [post]
+func (h *Handler) HoldSlot(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	var input HoldSlotReq
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid input: "+err.Error(), c)
+		return
+	}
+	at, err := time.Parse("2006-01-02 15:04", input.Time)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid time", c)
+		return
+	}
+
+	hold := &models.SlotHold{MasterID: input.MasterID, UserID: claims.Id, Time: at}
+	if err := h.s.Appointments.HoldSlot(c.Request.Context(), hold); err != nil {
+		var valErr service.ValidationError
+		switch {
+		case errors.As(err, &valErr):
+			newErrorResponse(http.StatusBadRequest, valErr.Msg, c)
+		case errors.Is(err, service.ErrMasterUnavaliable):
+			newErrorResponse(http.StatusConflict, "master unavaliable", c)
+		case errors.Is(err, service.ErrAppointmentConflict):
+			newErrorResponse(http.StatusConflict, "appointment with this time already exists", c)
+		case errors.Is(err, service.ErrSlotHeld):
+			newErrorResponse(http.StatusConflict, "slot is being booked by another client", c)
+		default:
+			newErrorResponse(http.StatusInternalServerError, "can't hold slot", c)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, hold)
+}
+
+// @Summary Release held slot
+// @Description Releases the current client's hold of the slot
+// @Tags appointments
+// @Param master_id query int true "master id"
+// @Param time query string true "slot time, YYYY-MM-DD HH:MM"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /appointments/hold [delete]
+func (h *Handler) ReleaseSlot(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	masterId, err := strconv.ParseInt(c.Query("master_id"), 10, 64)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid master_id", c)
+		return
+	}
+	at, err := time.Parse("2006-01-02 15:04", c.Query("time"))
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid time", c)
+		return
+	}
+
+	if err := h.s.Appointments.ReleaseSlot(c.Request.Context(), claims.Id, masterId, at); err != nil {
+		if errors.Is(err, service.ErrHoldNotFound) {
+			newErrorResponse(http.StatusNotFound, "hold not found", c)
+			return
+		}
+		newErrorResponse(http.StatusInternalServerError, "can't release slot", c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
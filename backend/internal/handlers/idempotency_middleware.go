@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strawberry/internal/models"
+	"strawberry/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	idempotentReplayed   = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLen  = 255
+	maxIdempotentBodySize = 1 << 20
+)
+
+// responseRecorder copies the response body while writing it to the client.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// idempotencyMiddleware makes requests with an Idempotency-Key header safe to retry:
+// a retry gets the stored response of the first request instead of executing again.
+// Must run after authMiddleware, keys are scoped per user.
+func (h *Handler) idempotencyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Error: "idempotency key is too long"})
+			return
+		}
+
+		claims, ok := getClaims(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Error: "unauthorized"})
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxIdempotentBodySize+1))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Error: "can't read request body"})
+			return
+		}
+		if len(body) > maxIdempotentBodySize {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: "request body is too large"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		fingerprint := requestFingerprint(c.Request, body)
+
+		rec, err := h.s.Idempotency.Begin(ctx, claims.Id, key, fingerprint)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrIdempotencyMismatch):
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, ErrorResponse{Error: err.Error()})
+			case errors.Is(err, service.ErrIdempotencyInProgress):
+				c.AbortWithStatusJSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+			default:
+				c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Error: "can't check idempotency key"})
+			}
+			return
+		}
+		if rec != nil {
+			c.Header(idempotentReplayed, "true")
+			c.Data(rec.Status, rec.ContentType, rec.Body)
+			c.Abort()
+			return
+		}
+
+		w := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = w
+
+		// A panicking handler is recovered outside this middleware; release
+		// the key so that retries are not stuck as in progress until the TTL.
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			_ = h.s.Idempotency.Abort(ctx, claims.Id, key)
+			if r := recover(); r != nil {
+				panic(r)
+			}
+		}()
+
+		c.Next()
+
+		if w.Status() >= http.StatusInternalServerError {
+			return
+		}
+		completed = true
+		_ = h.s.Idempotency.Complete(ctx, claims.Id, key, &models.IdempotencyRecord{
+			Fingerprint: fingerprint,
+			Status:      w.Status(),
+			ContentType: w.Header().Get("Content-Type"),
+			Body:        w.body.Bytes(),
+		})
+	}
+}
+
+func requestFingerprint(r *http.Request, body []byte) string {
+	sum := sha256.New()
+	sum.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	sum.Write(body)
+	return hex.EncodeToString(sum.Sum(nil))
+}
@@ -7,89 +7,77 @@ import (
 	"strawberry/internal/models"
 	"strawberry/internal/service"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-type MasterFilter struct {
-	Specialization string  `form:"specialization"`
-	MinRating      float64 `form:"min_rating"`
-}
-
 // @Summary Get list of masters
-// @Description Get masters filtered by specialization and/or minimum average rating
+// @Description Get masters filtered by specialization, minimum average rating and free slots on a date, in pages. The rating order uses the Bayesian score from the rating summary, so a few reviews cannot outrank a long track record. The number of all matching masters is returned in the X-Total-Count header
 // @Tags masters
 // @Accept json
 // @Produce json
 // @Param specialization query string false "Filter by specialization"
 // @Param min_rating query number false "Filter by minimum average rating"
+// @Param free_on query string false "Only masters with a free slot on this date, YYYY-MM-DD"
+// @Param sort query string false "rating (default), reviews, newest or name"
+// @Param limit query int false "Page size, 20 by default, at most 100"
+// @Param offset query int false "Number of masters to skip"
 // @Success 200 {array} models.User
+// @Header 200 {integer} X-Total-Count "number of all matching masters"
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /masters [get]
 func (h *Handler) GetMasters(c *gin.Context) {
-	specialization := c.Query("specialization")
-	minRatingStr := c.Query("min_rating")
-
-	var mastersBySpec []models.User
-	var mastersByRating []models.User
-	var err error
-
-	if specialization != "" {
-		mastersBySpec, err = h.s.Users.GetMastersBySpecialization(c.Request.Context(), specialization)
+	f := models.MasterFilter{
+		Specialization: c.Query("specialization"),
+		Sort:           c.Query("sort"),
+	}
+	if v := c.Query("min_rating"); v != "" {
+		minRating, err := strconv.ParseFloat(v, 64)
 		if err != nil {
-			newErrorResponse(http.StatusInternalServerError, "failed to get masters by specialization", c)
+			newErrorResponse(http.StatusBadRequest, "invalid min_rating", c)
 			return
 		}
+		f.MinRating = minRating
 	}
-
-	if minRatingStr != "" {
-		minRating, err := strconv.ParseFloat(minRatingStr, 64)
+	if v := c.Query("free_on"); v != "" {
+		d, err := time.Parse("2006-01-02", v)
 		if err != nil {
-			newErrorResponse(http.StatusBadRequest, "invalid min_rating", c)
+			newErrorResponse(http.StatusBadRequest, "invalid free_on, expected YYYY-MM-DD", c)
 			return
 		}
-
-		mastersByRating, err = h.s.Users.GetMastersByRating(c.Request.Context())
-		if err != nil {
-			newErrorResponse(http.StatusInternalServerError, "failed to get masters by rating", c)
+		f.FreeOn = &d
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			newErrorResponse(http.StatusBadRequest, "invalid limit", c)
 			return
 		}
-
-		filtered := []models.User{}
-		for _, m := range mastersByRating {
-			if m.AverageRating >= minRating {
-				filtered = append(filtered, m)
-			}
+		f.Limit = limit
+	}
+	if v := c.Query("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			newErrorResponse(http.StatusBadRequest, "invalid offset", c)
+			return
 		}
-		mastersByRating = filtered
+		f.Offset = offset
 	}
-	var result []models.User
 
-	switch {
-	case specialization != "" && minRatingStr != "":
-		mById := make(map[int64]models.User)
-		for _, m := range mastersBySpec {
-			mById[m.Id] = m
-		}
-		for _, m := range mastersByRating {
-			if _, ok := mById[m.Id]; ok {
-				result = append(result, m)
-			}
-		}
-	case specialization != "":
-		result = mastersBySpec
-	case minRatingStr != "":
-		result = mastersByRating
-	default:
-		result, err = h.s.Users.GetMastersByRating(c.Request.Context())
-		if err != nil {
-			newErrorResponse(http.StatusInternalServerError, "failed to get masters", c)
+	page, err := h.s.Users.FindMasters(c.Request.Context(), f)
+	if err != nil {
+		var valErr service.ValidationError
+		if errors.As(err, &valErr) {
+			newErrorResponse(http.StatusBadRequest, valErr.Msg, c)
 			return
 		}
+		newErrorResponse(http.StatusInternalServerError, "failed to get masters", c)
+		return
 	}
-
-	c.JSON(http.StatusOK, result)
+	c.Header("X-Total-Count", strconv.Itoa(page.Total))
+	c.JSON(http.StatusOK, page.Masters)
 }
 
 // @Summary Get master by username
@@ -340,20 +328,80 @@ func (h *Handler) GetMasterAppointments(c *gin.Context) {
 
 // Search godoc
 // @Summary      Search Masters
-// @Description  Search Master by full_name, username or specialization
+// @Description  Full-text search of masters by full name, username, specialization and bio. Russian and English words match by their stems, misspellings by similarity. Results are ordered by relevance combined with rating; the next page cursor is returned in the X-Next-Cursor header
 // @Tags         users
 // @Accept       json
 // @Produce      json
-// @Param        key query string true "Search query (searching for full_name, username, specialization)"
+// @Param        key query string true "Search query"
+// @Param        limit query int false "page size, 20 by default, at most 100"
+// @Param        cursor query string false "X-Next-Cursor of the previous page"
 // @Success      200 {array} models.User
+// @Header       200 {string} X-Next-Cursor "cursor of the next page, absent on the last one"
+// @Failure      400 {object} ErrorResponse
 // @Failure      500 {object} ErrorResponse
 // @Router       /search [get]
 func (h *Handler) Search(c *gin.Context) {
-	query := c.Query("key")
-	masters, err := h.s.Users.Search(c.Request.Context(), query)
+	q := models.SearchQuery{
+		Text:   c.Query("key"),
+		Cursor: c.Query("cursor"),
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			newErrorResponse(http.StatusBadRequest, "invalid limit", c)
+			return
+		}
+		q.Limit = limit
+	}
+
+	page, err := h.s.Users.Search(c.Request.Context(), q)
 	if err != nil {
-		newErrorResponse(http.StatusInternalServerError, "cannot get appointments", c)
+		var valErr service.ValidationError
+		if errors.As(err, &valErr) {
+			newErrorResponse(http.StatusBadRequest, valErr.Msg, c)
+			return
+		}
+		newErrorResponse(http.StatusInternalServerError, "cannot search masters", c)
+		return
+	}
+	if page.NextCursor != "" {
+		c.Header("X-Next-Cursor", page.NextCursor)
+	}
+	c.JSON(http.StatusOK, page.Masters)
+}
+
+// Suggest godoc
+// @Summary      Search suggestions
+// @Description  Autocomplete for the search bar: masters and specializations with a word of the name, username or specialization starting with the prefix, most popular first. Results are cached for a few minutes, so the endpoint can be called on every keystroke
+// @Tags         users
+// @Produce      json
+// @Param        q query string true "Typed prefix"
+// @Param        limit query int false "number of suggestions, 8 by default, at most 20"
+// @Success      200 {array} models.Suggestion
+// @Failure      400 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /search/suggest [get]
+func (h *Handler) Suggest(c *gin.Context) {
+	q := models.SuggestQuery{Prefix: c.Query("q")}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			newErrorResponse(http.StatusBadRequest, "invalid limit", c)
+			return
+		}
+		q.Limit = limit
+	}
+
+	suggestions, err := h.s.Users.Suggest(c.Request.Context(), q)
+	if err != nil {
+		var valErr service.ValidationError
+		if errors.As(err, &valErr) {
+			newErrorResponse(http.StatusBadRequest, valErr.Msg, c)
+			return
+		}
+		newErrorResponse(http.StatusInternalServerError, "cannot get suggestions", c)
 		return
 	}
-	c.JSON(http.StatusOK, masters)
+	c.Header("Cache-Control", "public, max-age=60")
+	c.JSON(http.StatusOK, suggestions)
 }
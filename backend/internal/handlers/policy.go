@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strawberry/internal/models"
+	"strawberry/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SetCancellationPolicyReq struct {
+	FreeCancelHours     int  `json:"free_cancel_hours"`
+	NoShowThreshold     int  `json:"no_show_threshold"`
+	AutoConfirm         bool `json:"auto_confirm"`
+	RequireConfirmation bool `json:"require_confirmation"`
+}
+
+// @Summary Get master's cancellation policy
+// @Description Returns the free-cancel deadline and confirmation rules of the master
+// @Tags policy
+// @Produce json
+// @Param id path int true "Master ID"
+// @Success 200 {object} models.CancellationPolicy
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/{id}/policy [get]
+func (h *Handler) GetCancellationPolicy(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid id", c)
+		return
+	}
+
+	policy, err := h.s.Appointments.GetPolicy(c.Request.Context(), id)
+	if err != nil {
+		newErrorResponse(http.StatusInternalServerError, "cannot get policy", c)
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// @Summary Set cancellation policy
+// @Description Sets the free-cancel deadline (in hours) and how bookings are confirmed. With auto_confirm bookings are confirmed right away, otherwise they stay pending until the master confirms them. require_confirmation keeps bookings of clients reaching the no-show threshold pending even with auto_confirm
+// @Tags policy
+// @Accept json
+// @Produce json
+// @Param input body SetCancellationPolicyReq true "policy"
+// @Success 200 {object} models.CancellationPolicy
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /masters/policy [put]
+func (h *Handler) SetCancellationPolicy(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	var input SetCancellationPolicyReq
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(http.StatusBadRequest, "bad data", c)
+		return
+	}
+
+	policy := &models.CancellationPolicy{
+		MasterID:            claims.Id,
+		FreeCancelHours:     input.FreeCancelHours,
+		NoShowThreshold:     input.NoShowThreshold,
+		AutoConfirm:         input.AutoConfirm,
+		RequireConfirmation: input.RequireConfirmation,
+	}
+	if err := h.s.Appointments.SetPolicy(c.Request.Context(), policy); err != nil {
+		var valErr service.ValidationError
+		switch {
+		case errors.As(err, &valErr):
+			newErrorResponse(http.StatusBadRequest, valErr.Msg, c)
+		case errors.Is(err, service.ErrNotMaster):
+			newErrorResponse(http.StatusForbidden, "only masters have cancellation policy", c)
+		default:
+			newErrorResponse(http.StatusInternalServerError, "cannot set policy", c)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// @Summary Get client's visit statistics
+// @Description Returns completed visits, cancellations, late cancellations and no-shows of the client. Only for masters the client has booked with
+// @Tags policy
+// @Produce json
+// @Param id path int true "Client ID"
+// @Success 200 {object} models.ClientStats
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /masters/clients/{id}/stats [get]
+func (h *Handler) GetClientStats(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	clientId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid id", c)
+		return
+	}
+
+	stats, err := h.s.Appointments.GetClientStats(c.Request.Context(), claims.Id, clientId)
+	if err != nil {
+		if errors.Is(err, service.ErrNotMaster) {
+			newErrorResponse(http.StatusForbidden, "only masters can see client stats", c)
+			return
+		}
+		if errors.Is(err, service.ErrNotYourClient) {
+			newErrorResponse(http.StatusForbidden, err.Error(), c)
+			return
+		}
+		newErrorResponse(http.StatusInternalServerError, "cannot get client stats", c)
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
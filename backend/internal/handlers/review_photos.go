@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strawberry/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AddReviewPhoto godoc
+// @Summary Прикрепить фото к отзыву
+// @Description JPEG, PNG или WebP до 5 МБ, не больше 5 фото на отзыв. Только автор отзыва
+// @Tags reviews
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "ID отзыва"
+// @Param photo formData file true "Фото"
+// @Success 201 {object} models.ReviewPhoto
+// @Failure 400 {object} ErrorResponse "Неверный файл"
+// @Failure 401 {object} ErrorResponse "Неавторизован"
+// @Failure 403 {object} ErrorResponse "Чужой отзыв"
+// @Failure 404 {object} ErrorResponse "Отзыв не найден"
+// @Failure 409 {object} ErrorResponse "Достигнут лимит фото"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /reviews/{id}/photos [post]
+func (h *Handler) AddReviewPhoto(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid review id", c)
+		return
+	}
+
+	fileH, err := c.FormFile("photo")
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "no photo provided", c)
+		return
+	}
+	file, err := fileH.Open()
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "can't open file", c)
+		return
+	}
+	defer file.Close()
+
+	photo, err := h.s.Reviews.AddPhoto(c.Request.Context(), claims.Id, id, file, fileH.Size)
+	if err != nil {
+		reviewPhotoErrorResponse(err, c)
+		return
+	}
+	c.JSON(http.StatusCreated, photo)
+}
+
+// GetReviewPhoto godoc
+// @Summary Получить фото отзыва
+// @Tags reviews
+// @Produce image/jpeg,image/png,image/webp
+// @Param id path int true "ID отзыва"
+// @Param photoId path int true "ID фото"
+// @Success 200 {file} binary
+// @Failure 400 {object} ErrorResponse "Неверный ID"
+// @Failure 404 {object} ErrorResponse "Фото не найдено"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /reviews/{id}/photos/{photoId} [get]
+func (h *Handler) GetReviewPhoto(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid review id", c)
+		return
+	}
+	photoId, err := strconv.ParseInt(c.Param("photoId"), 10, 64)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid photo id", c)
+		return
+	}
+
+	file, photo, err := h.s.Reviews.GetPhoto(c.Request.Context(), id, photoId)
+	if err != nil {
+		reviewPhotoErrorResponse(err, c)
+		return
+	}
+	sendFile(c, file, photo.ContentType, fmt.Sprint(photo.Id), photo.Size)
+}
+
+// DeleteReviewPhoto godoc
+// @Summary Удалить фото отзыва
+// @Tags reviews
+// @Security BearerAuth
+// @Param id path int true "ID отзыва"
+// @Param photoId path int true "ID фото"
+// @Success 204 "Фото удалено"
+// @Failure 400 {object} ErrorResponse "Неверный ID"
+// @Failure 401 {object} ErrorResponse "Неавторизован"
+// @Failure 403 {object} ErrorResponse "Чужой отзыв"
+// @Failure 404 {object} ErrorResponse "Отзыв или фото не найдены"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /reviews/{id}/photos/{photoId} [delete]
+func (h *Handler) DeleteReviewPhoto(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid review id", c)
+		return
+	}
+	photoId, err := strconv.ParseInt(c.Param("photoId"), 10, 64)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid photo id", c)
+		return
+	}
+
+	if err := h.s.Reviews.DeletePhoto(c.Request.Context(), claims.Id, id, photoId); err != nil {
+		reviewPhotoErrorResponse(err, c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func reviewPhotoErrorResponse(err error, c *gin.Context) {
+	var valErr service.ValidationError
+	switch {
+	case errors.As(err, &valErr):
+		newErrorResponse(http.StatusBadRequest, valErr.Msg, c)
+	case errors.Is(err, service.ErrReviewNotFound), errors.Is(err, service.ErrPhotoNotFound):
+		newErrorResponse(http.StatusNotFound, err.Error(), c)
+	case errors.Is(err, service.ErrUnauthorized):
+		newErrorResponse(http.StatusForbidden, "not your review", c)
+	case errors.Is(err, service.ErrTooManyPhotos):
+		newErrorResponse(http.StatusConflict, err.Error(), c)
+	default:
+		newErrorResponse(http.StatusInternalServerError, "cannot process review photo", c)
+	}
+}
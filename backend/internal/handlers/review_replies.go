@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strawberry/internal/models"
+	"strawberry/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewReplyReq struct {
+	Text string `json:"text" binding:"required"`
+}
+
+// CreateReviewReply godoc
+// @Summary Ответить на отзыв
+// @Description Публичный ответ мастера на отзыв о нём, не больше одного на отзыв. Автор отзыва получает уведомление
+// @Tags reviews
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID отзыва"
+// @Param reply body ReviewReplyReq true "Текст ответа"
+// @Success 201 {object} models.ReviewReply
+// @Failure 400 {object} ErrorResponse "Неверные данные запроса"
+// @Failure 401 {object} ErrorResponse "Неавторизован"
+// @Failure 403 {object} ErrorResponse "Отзыв оставлен другому мастеру"
+// @Failure 404 {object} ErrorResponse "Отзыв не найден"
+// @Failure 409 {object} ErrorResponse "Ответ уже есть"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /reviews/{id}/reply [post]
+func (h *Handler) CreateReviewReply(c *gin.Context) {
+	h.saveReviewReply(c, h.s.Reviews.CreateReply, http.StatusCreated)
+}
+
+// UpdateReviewReply godoc
+// @Summary Изменить ответ на отзыв
+// @Tags reviews
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID отзыва"
+// @Param reply body ReviewReplyReq true "Текст ответа"
+// @Success 200 {object} models.ReviewReply
+// @Failure 400 {object} ErrorResponse "Неверные данные запроса"
+// @Failure 401 {object} ErrorResponse "Неавторизован"
+// @Failure 403 {object} ErrorResponse "Отзыв оставлен другому мастеру"
+// @Failure 404 {object} ErrorResponse "Отзыв или ответ не найден"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /reviews/{id}/reply [put]
+func (h *Handler) UpdateReviewReply(c *gin.Context) {
+	h.saveReviewReply(c, h.s.Reviews.UpdateReply, http.StatusOK)
+}
+
+func (h *Handler) saveReviewReply(c *gin.Context, save func(ctx context.Context, reply *models.ReviewReply) error, status int) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid review id", c)
+		return
+	}
+
+	var input ReviewReplyReq
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid input data", c)
+		return
+	}
+
+	reply := &models.ReviewReply{ReviewId: id, MasterId: claims.Id, Text: input.Text}
+	if err := save(c.Request.Context(), reply); err != nil {
+		replyErrorResponse(err, c)
+		return
+	}
+	c.JSON(status, reply)
+}
+
+// DeleteReviewReply godoc
+// @Summary Удалить ответ на отзыв
+// @Tags reviews
+// @Security BearerAuth
+// @Param id path int true "ID отзыва"
+// @Success 204 "Ответ удалён"
+// @Failure 400 {object} ErrorResponse "Неверный ID отзыва"
+// @Failure 401 {object} ErrorResponse "Неавторизован"
+// @Failure 403 {object} ErrorResponse "Отзыв оставлен другому мастеру"
+// @Failure 404 {object} ErrorResponse "Отзыв или ответ не найден"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /reviews/{id}/reply [delete]
+func (h *Handler) DeleteReviewReply(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid review id", c)
+		return
+	}
+
+	if err := h.s.Reviews.DeleteReply(c.Request.Context(), claims.Id, id); err != nil {
+		replyErrorResponse(err, c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func replyErrorResponse(err error, c *gin.Context) {
+	var valErr service.ValidationError
+	switch {
+	case errors.As(err, &valErr):
+		newErrorResponse(http.StatusBadRequest, valErr.Msg, c)
+	case errors.Is(err, service.ErrReviewNotFound), errors.Is(err, service.ErrReplyNotFound):
+		newErrorResponse(http.StatusNotFound, err.Error(), c)
+	case errors.Is(err, service.ErrNotReviewedMaster):
+		newErrorResponse(http.StatusForbidden, err.Error(), c)
+	case errors.Is(err, service.ErrAlreadyReplied):
+		newErrorResponse(http.StatusConflict, err.Error(), c)
+	default:
+		newErrorResponse(http.StatusInternalServerError, "cannot save review reply", c)
+	}
+}
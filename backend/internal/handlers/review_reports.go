@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strawberry/internal/models"
+	"strawberry/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportReviewReq struct {
+	Reason  string `json:"reason" binding:"required" example:"spam"`
+	Comment string `json:"comment"`
+}
+
+type ResolveReportsReq struct {
+	Decision string `json:"decision" binding:"required" example:"dismissed"`
+}
+
+// ReportReview godoc
+// @Summary Пожаловаться на отзыв
+// @Description Причина: spam, offensive, fake или other. После жалоб от нескольких разных пользователей отзыв скрывается до решения модератора
+// @Tags reviews
+// @Security BearerAuth
+// @Accept json
+// @Param id path int true "ID отзыва"
+// @Param report body ReportReviewReq true "Жалоба"
+// @Success 204 "Жалоба принята"
+// @Failure 400 {object} ErrorResponse "Неверные данные запроса"
+// @Failure 401 {object} ErrorResponse "Неавторизован"
+// @Failure 403 {object} ErrorResponse "Жалоба на собственный отзыв"
+// @Failure 404 {object} ErrorResponse "Отзыв не найден"
+// @Failure 409 {object} ErrorResponse "Жалоба уже отправлена"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /reviews/{id}/report [post]
+func (h *Handler) ReportReview(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid review id", c)
+		return
+	}
+
+	var input ReportReviewReq
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid input data", c)
+		return
+	}
+
+	err = h.s.Reviews.Report(c.Request.Context(), &models.ReviewReport{
+		ReviewId:   id,
+		ReporterId: claims.Id,
+		Reason:     input.Reason,
+		Comment:    input.Comment,
+	})
+	if err != nil {
+		moderationErrorResponse(err, c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetModerationQueue godoc
+// @Summary Очередь модерации отзывов
+// @Description Отзывы с жалобами в заданном статусе. В открытой очереди также скрытые отзывы без жалоб. Только для модераторов
+// @Tags moderation
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "open (по умолчанию), dismissed или removed"
+// @Success 200 {array} models.ModerationCase
+// @Failure 400 {object} ErrorResponse "Неверный статус"
+// @Failure 401 {object} ErrorResponse "Неавторизован"
+// @Failure 403 {object} ErrorResponse "Не модератор"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /moderation/reviews [get]
+func (h *Handler) GetModerationQueue(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	cases, err := h.s.Reviews.ModerationQueue(c.Request.Context(), claims.Id, c.Query("status"))
+	if err != nil {
+		moderationErrorResponse(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, cases)
+}
+
+// ResolveReviewReports godoc
+// @Summary Решение по жалобам на отзыв
+// @Description dismissed отклоняет жалобы и возвращает скрытый отзыв, removed окончательно убирает отзыв. Только для модераторов
+// @Tags moderation
+// @Security BearerAuth
+// @Accept json
+// @Param id path int true "ID отзыва"
+// @Param decision body ResolveReportsReq true "Решение"
+// @Success 204 "Жалобы закрыты"
+// @Failure 400 {object} ErrorResponse "Неверные данные запроса"
+// @Failure 401 {object} ErrorResponse "Неавторизован"
+// @Failure 403 {object} ErrorResponse "Не модератор"
+// @Failure 404 {object} ErrorResponse "Нет открытых жалоб"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /moderation/reviews/{id} [put]
+func (h *Handler) ResolveReviewReports(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid review id", c)
+		return
+	}
+
+	var input ResolveReportsReq
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid input data", c)
+		return
+	}
+
+	if err := h.s.Reviews.Resolve(c.Request.Context(), claims.Id, id, input.Decision); err != nil {
+		moderationErrorResponse(err, c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func moderationErrorResponse(err error, c *gin.Context) {
+	var valErr service.ValidationError
+	switch {
+	case errors.As(err, &valErr):
+		newErrorResponse(http.StatusBadRequest, valErr.Msg, c)
+	case errors.Is(err, service.ErrReviewNotFound), errors.Is(err, service.ErrNothingToResolve):
+		newErrorResponse(http.StatusNotFound, err.Error(), c)
+	case errors.Is(err, service.ErrNotModerator), errors.Is(err, service.ErrOwnReview):
+		newErrorResponse(http.StatusForbidden, err.Error(), c)
+	case errors.Is(err, service.ErrAlreadyReported):
+		newErrorResponse(http.StatusConflict, err.Error(), c)
+	default:
+		newErrorResponse(http.StatusInternalServerError, "cannot process moderation request", c)
+	}
+}
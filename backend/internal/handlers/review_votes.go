@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strawberry/internal/models"
+	"strawberry/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewVoteReq struct {
+	Helpful *bool `json:"helpful" binding:"required" example:"true"`
+}
+
+// VoteReview godoc
+// @Summary Оценить полезность отзыва
+// @Description Один голос от пользователя, повторный запрос меняет голос. Голосовать за свой отзыв нельзя
+// @Tags reviews
+// @Security BearerAuth
+// @Accept json
+// @Param id path int true "ID отзыва"
+// @Param vote body ReviewVoteReq true "Голос"
+// @Success 204 "Голос учтён"
+// @Failure 400 {object} ErrorResponse "Неверные данные запроса"
+// @Failure 401 {object} ErrorResponse "Неавторизован"
+// @Failure 403 {object} ErrorResponse "Собственный отзыв"
+// @Failure 404 {object} ErrorResponse "Отзыв не найден"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /reviews/{id}/vote [put]
+func (h *Handler) VoteReview(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid review id", c)
+		return
+	}
+
+	var input ReviewVoteReq
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid input data", c)
+		return
+	}
+
+	err = h.s.Reviews.Vote(c.Request.Context(), &models.ReviewVote{
+		ReviewId: id,
+		UserId:   claims.Id,
+		Helpful:  *input.Helpful,
+	})
+	if err != nil {
+		voteErrorResponse(err, c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteReviewVote godoc
+// @Summary Отозвать голос за отзыв
+// @Tags reviews
+// @Security BearerAuth
+// @Param id path int true "ID отзыва"
+// @Success 204 "Голос отозван"
+// @Failure 400 {object} ErrorResponse "Неверный ID"
+// @Failure 401 {object} ErrorResponse "Неавторизован"
+// @Failure 404 {object} ErrorResponse "Голос не найден"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /reviews/{id}/vote [delete]
+func (h *Handler) DeleteReviewVote(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid review id", c)
+		return
+	}
+
+	if err := h.s.Reviews.DeleteVote(c.Request.Context(), claims.Id, id); err != nil {
+		voteErrorResponse(err, c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func voteErrorResponse(err error, c *gin.Context) {
+	switch {
+	case errors.Is(err, service.ErrReviewNotFound), errors.Is(err, service.ErrVoteNotFound):
+		newErrorResponse(http.StatusNotFound, err.Error(), c)
+	case errors.Is(err, service.ErrVoteOwnReview):
+		newErrorResponse(http.StatusForbidden, err.Error(), c)
+	default:
+		newErrorResponse(http.StatusInternalServerError, "cannot process vote", c)
+	}
+}
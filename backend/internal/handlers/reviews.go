@@ -6,26 +6,34 @@ import (
 	"strawberry/internal/models"
 	"strawberry/internal/service"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type CreateReviewReq struct {
-	MasterId int64  `json:"master_id"`
-	Comment  string `json:"comment"`
-	Rating   int    `json:"rating"`
+	AppointmentId int64  `json:"appointment_id" binding:"required"`
+	MasterId      int64  `json:"master_id"`
+	Comment       string `json:"comment"`
+	Rating        int    `json:"rating"`
 }
 
 // CreateReview создает новый отзыв
 // @Summary Создать отзыв
+// @Description Отзыв оставляется на завершённую запись клиента, не больше одного на запись. Текст проверяется автоматически: отзыв может быть отклонён или скрыт до проверки модератором (moderation = hidden)
 // @Tags reviews
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param review body CreateReviewReq true "Данные отзыва"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ"
 // @Success 201 {object} models.Review
 // @Failure 400 {object} ErrorResponse "Неверные данные запроса"
 // @Failure 401 {object} ErrorResponse "Неавторизован"
+// @Failure 403 {object} ErrorResponse "Запись чужая или ещё не завершена"
+// @Failure 404 {object} ErrorResponse "Запись не найдена"
+// @Failure 409 {object} ErrorResponse "На запись уже есть отзыв или запрос с этим ключом ещё выполняется"
+// @Failure 422 {object} ErrorResponse "Отзыв отклонён проверкой или ключ идемпотентности использован с другим запросом"
 // @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
 // @Router /reviews [post]
 func (h *Handler) CreateReview(c *gin.Context) {
@@ -42,31 +50,78 @@ func (h *Handler) CreateReview(c *gin.Context) {
 	}
 
 	review := &models.Review{
-		UserId:   claims.Id,
-		MasterId: input.MasterId,
-		Rating:   input.Rating,
-		Comment:  input.Comment,
+		UserId:        claims.Id,
+		MasterId:      input.MasterId,
+		AppointmentId: &input.AppointmentId,
+		Rating:        input.Rating,
+		Comment:       input.Comment,
 	}
 
 	if err := h.s.Reviews.Create(c.Request.Context(), review); err != nil {
-		if errors.Is(err, service.ErrNoPastAppointments) {
-			newErrorResponse(http.StatusForbidden, "you can't make a review without visiting the master", c)
-			return
+		var (
+			valErr      service.ValidationError
+			rejectedErr service.ReviewRejectedError
+		)
+		switch {
+		case errors.As(err, &valErr):
+			newErrorResponse(http.StatusBadRequest, valErr.Msg, c)
+		case errors.As(err, &rejectedErr):
+			newErrorResponse(http.StatusUnprocessableEntity, rejectedErr.Error(), c)
+		case errors.Is(err, service.ErrAppointmentNotFound):
+			newErrorResponse(http.StatusNotFound, "appointment not found", c)
+		case errors.Is(err, service.ErrNotReviewable), errors.Is(err, service.ErrAppointmentNotCompleted):
+			newErrorResponse(http.StatusForbidden, err.Error(), c)
+		case errors.Is(err, service.ErrAlreadyReviewed):
+			newErrorResponse(http.StatusConflict, err.Error(), c)
+		default:
+			newErrorResponse(http.StatusInternalServerError, "cannot create review", c)
 		}
-		newErrorResponse(http.StatusInternalServerError, "cannot create review", c)
 		return
 	}
 
-	c.JSON(http.StatusCreated, input)
+	c.JSON(http.StatusCreated, review)
+}
+
+// GetAwaitingReview godoc
+// @Summary Записи без отзыва
+// @Description Завершённые записи текущего клиента, на которые ещё не оставлен отзыв
+// @Tags reviews
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.Appointment
+// @Failure 401 {object} ErrorResponse "Неавторизован"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /reviews/pending [get]
+func (h *Handler) GetAwaitingReview(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	apts, err := h.s.Reviews.AwaitingReview(c.Request.Context(), claims.Id)
+	if err != nil {
+		newErrorResponse(http.StatusInternalServerError, "cannot get appointments awaiting review", c)
+		return
+	}
+	c.JSON(http.StatusOK, apts)
 }
 
 // GetReviewsByMasterId получает отзывы по ID мастера
 // @Summary Получить отзывы мастера
+// @Description Постраничный список опубликованных отзывов. Курсор следующей страницы возвращается в заголовке X-Next-Cursor
 // @Tags reviews
 // @Produce json
 // @Param master_id path int true "ID мастера"
+// @Param sort query string false "newest (по умолчанию), highest, lowest или most_helpful"
+// @Param rating query []int false "оценки, через запятую или повтором параметра" collectionFormat(multi)
+// @Param with_photos query bool false "только отзывы с фото"
+// @Param with_comment query bool false "только отзывы с текстом"
+// @Param limit query int false "размер страницы, по умолчанию 20, не больше 100"
+// @Param cursor query string false "X-Next-Cursor предыдущей страницы"
 // @Success 200 {array} models.Review
-// @Failure 400 {object} ErrorResponse "Неверный ID мастера"
+// @Header 200 {string} X-Next-Cursor "курсор следующей страницы, отсутствует на последней"
+// @Failure 400 {object} ErrorResponse "Неверные параметры запроса"
 // @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
 // @Router /reviews/master/{master_id} [get]
 func (h *Handler) GetReviewsByMasterId(c *gin.Context) {
@@ -77,18 +132,95 @@ func (h *Handler) GetReviewsByMasterId(c *gin.Context) {
 		return
 	}
 
-	reviews, err := h.s.Reviews.GetByMasterId(c.Request.Context(), masterId)
+	f := models.ReviewFilter{
+		MasterID: masterId,
+		Sort:     c.Query("sort"),
+		Cursor:   c.Query("cursor"),
+	}
+	for _, v := range c.QueryArray("rating") {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s == "" {
+				continue
+			}
+			rating, err := strconv.Atoi(s)
+			if err != nil {
+				newErrorResponse(http.StatusBadRequest, "invalid rating", c)
+				return
+			}
+			f.Ratings = append(f.Ratings, rating)
+		}
+	}
+	for _, p := range []struct {
+		name string
+		dst  *bool
+	}{{"with_photos", &f.WithPhotos}, {"with_comment", &f.WithComment}} {
+		if v := c.Query(p.name); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				newErrorResponse(http.StatusBadRequest, "invalid "+p.name, c)
+				return
+			}
+			*p.dst = b
+		}
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			newErrorResponse(http.StatusBadRequest, "invalid limit", c)
+			return
+		}
+		f.Limit = limit
+	}
+
+	page, err := h.s.Reviews.GetByMasterId(c.Request.Context(), f)
 	if err != nil {
+		var valErr service.ValidationError
+		if errors.As(err, &valErr) {
+			newErrorResponse(http.StatusBadRequest, valErr.Msg, c)
+			return
+		}
 		newErrorResponse(http.StatusInternalServerError, "cannot get reviews", c)
 		return
 	}
+	if page.NextCursor != "" {
+		c.Header("X-Next-Cursor", page.NextCursor)
+	}
+	c.JSON(http.StatusOK, page.Reviews)
+}
+
+// GetReviewRevisions godoc
+// @Summary История правок отзыва
+// @Description Предыдущие версии отзыва, от самой ранней. Текущая версия в список не входит
+// @Tags reviews
+// @Produce json
+// @Param id path int true "ID отзыва"
+// @Success 200 {array} models.ReviewRevision
+// @Failure 400 {object} ErrorResponse "Неверный ID отзыва"
+// @Failure 404 {object} ErrorResponse "Отзыв не найден"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /reviews/{id}/revisions [get]
+func (h *Handler) GetReviewRevisions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid review id", c)
+		return
+	}
 
-	c.JSON(http.StatusOK, reviews)
+	revisions, err := h.s.Reviews.Revisions(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrReviewNotFound) {
+			newErrorResponse(http.StatusNotFound, err.Error(), c)
+			return
+		}
+		newErrorResponse(http.StatusInternalServerError, "cannot get review revisions", c)
+		return
+	}
+	c.JSON(http.StatusOK, revisions)
 }
 
 // UpdateReview godoc
 // @Summary Обновить отзыв
-// @Description Обновляет существующий отзыв пользователя
+// @Description Обновляет существующий отзыв пользователя. Прежняя версия сохраняется в истории правок, отзыв помечается как изменённый (edited_at)
 // @Tags reviews
 // @Accept json
 // @Produce json
@@ -98,6 +230,7 @@ func (h *Handler) GetReviewsByMasterId(c *gin.Context) {
 // @Failure 400 {object} ErrorResponse "Неверные данные или ID"
 // @Failure 401 {object} ErrorResponse "Неавторизован"
 // @Failure 404 {object} ErrorResponse "Отзыв не найден"
+// @Failure 422 {object} ErrorResponse "Отзыв отклонён проверкой"
 // @Failure 500 {object} ErrorResponse "Ошибка сервера"
 // @Security BearerAuth
 // @Router /reviews/{id} [put]
@@ -130,6 +263,11 @@ func (h *Handler) UpdateReview(c *gin.Context) {
 			newErrorResponse(http.StatusNotFound, "review not found", c)
 			return
 		}
+		var rejectedErr service.ReviewRejectedError
+		if errors.As(err, &rejectedErr) {
+			newErrorResponse(http.StatusUnprocessableEntity, rejectedErr.Error(), c)
+			return
+		}
 		newErrorResponse(http.StatusInternalServerError, "cannot update review", c)
 		return
 	}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strawberry/internal/service"
+	"strawberry/pkg/ical"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SetCalendarURLReq struct {
+	URL string `json:"url" binding:"required"`
+}
+
+// @Summary Import busy times from .ics file
+// @Description Replaces previously imported busy times of the master with events of the uploaded calendar. Imported times can't be booked
+// @Tags schedule
+// @Accept multipart/form-data
+// @Produce json
+// @Param calendar formData file true "iCalendar file"
+// @Success 200 {object} models.CalendarImport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /schedule/import [post]
+func (h *Handler) ImportCalendar(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	formFile, err := c.FormFile("calendar")
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "can't find file", c)
+		return
+	}
+	if formFile.Size > ical.MaxCalendarSize {
+		newErrorResponse(http.StatusBadRequest, "calendar file is too large", c)
+		return
+	}
+	file, err := formFile.Open()
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "can't open file", c)
+		return
+	}
+	defer file.Close()
+
+	imp, err := h.s.Schedules.ImportCalendar(c.Request.Context(), claims.Id, file)
+	if err != nil {
+		calendarImportError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, imp)
+}
+
+// @Summary Subscribe to .ics url
+// @Description Registers a published calendar of the master and imports its busy times immediately
+// @Tags schedule
+// @Accept json
+// @Produce json
+// @Param input body SetCalendarURLReq true "calendar url (http, https or webcal)"
+// @Success 200 {object} models.CalendarImport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /schedule/import/url [put]
+func (h *Handler) SetCalendarURL(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	var input SetCalendarURLReq
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid input: "+err.Error(), c)
+		return
+	}
+
+	imp, err := h.s.Schedules.SetCalendarURL(c.Request.Context(), claims.Id, input.URL)
+	if err != nil {
+		calendarImportError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, imp)
+}
+
+// @Summary Refresh imported busy times
+// @Description Fetches the registered calendar url again and replaces previously imported busy times
+// @Tags schedule
+// @Produce json
+// @Success 200 {object} models.CalendarImport
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /schedule/import/refresh [post]
+func (h *Handler) RefreshCalendar(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	imp, err := h.s.Schedules.RefreshCalendar(c.Request.Context(), claims.Id)
+	if err != nil {
+		calendarImportError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, imp)
+}
+
+func calendarImportError(err error, c *gin.Context) {
+	var valErr service.ValidationError
+	switch {
+	case errors.As(err, &valErr):
+		newErrorResponse(http.StatusBadRequest, valErr.Msg, c)
+	case errors.Is(err, service.ErrInvalidCalendar), errors.Is(err, service.ErrInvalidCalendarURL):
+		newErrorResponse(http.StatusBadRequest, err.Error(), c)
+	case errors.Is(err, service.ErrNotMaster):
+		newErrorResponse(http.StatusForbidden, "only masters can import calendars", c)
+	case errors.Is(err, service.ErrNoCalendarURL):
+		newErrorResponse(http.StatusNotFound, err.Error(), c)
+	case errors.Is(err, service.ErrCalendarUnavailable):
+		newErrorResponse(http.StatusBadGateway, err.Error(), c)
+	default:
+		newErrorResponse(http.StatusInternalServerError, "can't import calendar", c)
+	}
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"strawberry/internal/models"
 	"strawberry/internal/service"
 	"strconv"
 	"strings"
@@ -93,6 +94,11 @@ func (h *Handler) SetWorkingSlotsByWeekDay(c *gin.Context) {
 		input.Slots,
 	)
 	if err != nil {
+		var valErr service.ValidationError
+		if errors.As(err, &valErr) {
+			newErrorResponse(http.StatusBadRequest, valErr.Msg, c)
+			return
+		}
 		newErrorResponse(http.StatusInternalServerError, "can't set working slots", c)
 		return
 	}
@@ -169,6 +175,11 @@ func (h *Handler) SetWorkingSlotsByDate(c *gin.Context) {
 			newErrorResponse(http.StatusBadRequest, "bad date", c)
 			return
 		}
+		var valErr service.ValidationError
+		if errors.As(err, &valErr) {
+			newErrorResponse(http.StatusBadRequest, valErr.Msg, c)
+			return
+		}
 		newErrorResponse(http.StatusInternalServerError, "failed to set working slots", c)
 		return
 	}
@@ -212,3 +223,159 @@ func (h *Handler) DeleteWorkingSlotsByDate(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+type SetTemplateReq struct {
+	EffectiveFrom string              `json:"effective_from" binding:"required" example:"2025-06-01"`
+	Days          map[string][]string `json:"days" binding:"required"`
+}
+
+// @Summary Schedule weekly template
+// @Description Creates or replaces a whole-week template taking effect from the given date. Earlier templates stay in effect before it; days absent in the template are days off. Returns booked appointments that no longer fit
+// @Tags schedule
+// @Accept json
+// @Produce json
+// @Param input body SetTemplateReq true "template"
+// @Success 200 {object} models.TemplateChange
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /schedule/templates [put]
+func (h *Handler) SetScheduleTemplate(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	var input SetTemplateReq
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid input: "+err.Error(), c)
+		return
+	}
+
+	change, err := h.s.Schedules.SetTemplate(c.Request.Context(), claims.Id, input.EffectiveFrom, input.Days)
+	if err != nil {
+		var valErr service.ValidationError
+		switch {
+		case errors.As(err, &valErr):
+			newErrorResponse(http.StatusBadRequest, valErr.Msg, c)
+		case errors.Is(err, service.ErrBadDate):
+			newErrorResponse(http.StatusBadRequest, "bad date", c)
+		default:
+			newErrorResponse(http.StatusInternalServerError, "can't save schedule template", c)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, change)
+}
+
+// @Summary Get weekly templates
+// @Description Lists all weekly templates of the current master including historical ones
+// @Tags schedule
+// @Produce json
+// @Success 200 {array} models.WeeklyTemplate
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /schedule/templates [get]
+func (h *Handler) GetScheduleTemplates(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	templates, err := h.s.Schedules.GetTemplates(c.Request.Context(), claims.Id)
+	if err != nil {
+		newErrorResponse(http.StatusInternalServerError, "can't get schedule templates", c)
+		return
+	}
+	if templates == nil {
+		templates = []models.WeeklyTemplate{}
+	}
+	c.JSON(http.StatusOK, templates)
+}
+
+// @Summary Delete upcoming weekly template
+// @Description Deletes a template that hasn't taken effect yet
+// @Tags schedule
+// @Param date path string true "effective date, YYYY-MM-DD"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /schedule/templates/{date} [delete]
+func (h *Handler) DeleteScheduleTemplate(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	err := h.s.Schedules.DeleteTemplate(c.Request.Context(), claims.Id, c.Param("date"))
+	if err != nil {
+		var valErr service.ValidationError
+		switch {
+		case errors.As(err, &valErr):
+			newErrorResponse(http.StatusBadRequest, valErr.Msg, c)
+		case errors.Is(err, service.ErrBadDate):
+			newErrorResponse(http.StatusBadRequest, "bad date", c)
+		case errors.Is(err, service.ErrTemplateNotFound):
+			newErrorResponse(http.StatusNotFound, "template not found", c)
+		default:
+			newErrorResponse(http.StatusInternalServerError, "can't delete schedule template", c)
+		}
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+type SetWeekReq struct {
+	EffectiveFrom string              `json:"effective_from" example:"2025-06-01"`
+	Days          map[string][]string `json:"days" binding:"required"`
+	Dates         []models.DateSlots  `json:"dates"`
+}
+
+// @Summary Replace week schedule
+// @Description Atomically replaces the whole weekly template (in effect from effective_from, today by default) and a batch of date overrides. Slots must be HH:MM in ascending order without duplicates; an override without slots is removed. Returns future appointments that no longer fit
+// @Tags schedule
+// @Accept json
+// @Produce json
+// @Param input body SetWeekReq true "week schedule"
+// @Success 200 {object} models.TemplateChange
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /schedule/week [put]
+func (h *Handler) SetWeekSchedule(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	var input SetWeekReq
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid input: "+err.Error(), c)
+		return
+	}
+
+	change, err := h.s.Schedules.SetWeek(c.Request.Context(), claims.Id, input.EffectiveFrom, input.Days, input.Dates)
+	if err != nil {
+		var valErr service.ValidationError
+		switch {
+		case errors.As(err, &valErr):
+			newErrorResponse(http.StatusBadRequest, valErr.Msg, c)
+		case errors.Is(err, service.ErrBadDate):
+			newErrorResponse(http.StatusBadRequest, "bad date", c)
+		default:
+			newErrorResponse(http.StatusInternalServerError, "can't save week schedule", c)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, change)
+}
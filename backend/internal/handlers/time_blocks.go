@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strawberry/internal/models"
+	"strawberry/internal/service"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreateTimeBlockReq struct {
+	Start  string `json:"start" binding:"required" example:"2025-06-02 14:00"`
+	End    string `json:"end" binding:"required" example:"2025-06-02 15:30"`
+	Reason string `json:"reason" example:"Стоматолог"`
+}
+
+type CreateTimeBlockRes struct {
+	ID int64 `json:"id"`
+}
+
+// @Summary Block time interval
+// @Description Makes an arbitrary interval unavailable for booking without changing working slots. Fails if it overlaps booked appointments
+// @Tags schedule
+// @Accept json
+// @Produce json
+// @Param input body CreateTimeBlockReq true "interval in YYYY-MM-DD HH:MM format"
+// @Success 201 {object} CreateTimeBlockRes
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /schedule/blocks [post]
+func (h *Handler) CreateTimeBlock(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	var input CreateTimeBlockReq
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid input: "+err.Error(), c)
+		return
+	}
+	start, err := time.Parse("2006-01-02 15:04", input.Start)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid start, expected YYYY-MM-DD HH:MM", c)
+		return
+	}
+	end, err := time.Parse("2006-01-02 15:04", input.End)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid end, expected YYYY-MM-DD HH:MM", c)
+		return
+	}
+
+	id, err := h.s.Schedules.CreateBlock(c.Request.Context(), &models.TimeBlock{
+		MasterID: claims.Id,
+		StartsAt: start,
+		EndsAt:   end,
+		Reason:   input.Reason,
+	})
+	if err != nil {
+		var valErr service.ValidationError
+		switch {
+		case errors.As(err, &valErr):
+			newErrorResponse(http.StatusBadRequest, valErr.Msg, c)
+		case errors.Is(err, service.ErrNotMaster):
+			newErrorResponse(http.StatusForbidden, "only masters can block time", c)
+		case errors.Is(err, service.ErrBlockConflict):
+			newErrorResponse(http.StatusConflict, err.Error(), c)
+		default:
+			newErrorResponse(http.StatusInternalServerError, "can't create time block", c)
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, CreateTimeBlockRes{ID: id})
+}
+
+// @Summary Get own time blocks
+// @Description Lists manual and imported blocks of the current master between two dates inclusive
+// @Tags schedule
+// @Produce json
+// @Param from query string true "YYYY-MM-DD"
+// @Param to query string true "YYYY-MM-DD"
+// @Success 200 {array} models.TimeBlock
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /schedule/blocks [get]
+func (h *Handler) GetTimeBlocks(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	blocks, err := h.s.Schedules.GetBlocks(c.Request.Context(), claims.Id, c.Query("from"), c.Query("to"))
+	if err != nil {
+		if errors.Is(err, service.ErrBadDate) {
+			newErrorResponse(http.StatusBadRequest, "bad date range", c)
+			return
+		}
+		newErrorResponse(http.StatusInternalServerError, "can't get time blocks", c)
+		return
+	}
+	if blocks == nil {
+		blocks = []models.TimeBlock{}
+	}
+	c.JSON(http.StatusOK, blocks)
+}
+
+// @Summary Delete time block
+// @Description Removes a manual block, restoring availability of the interval
+// @Tags schedule
+// @Param id path int true "block id"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /schedule/blocks/{id} [delete]
+func (h *Handler) DeleteTimeBlock(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		newErrorResponse(http.StatusUnauthorized, "unauthorized", c)
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(http.StatusBadRequest, "invalid ID", c)
+		return
+	}
+
+	err = h.s.Schedules.DeleteBlock(c.Request.Context(), claims.Id, id)
+	if err != nil {
+		var valErr service.ValidationError
+		switch {
+		case errors.As(err, &valErr):
+			newErrorResponse(http.StatusBadRequest, valErr.Msg, c)
+		case errors.Is(err, service.ErrBlockNotFound):
+			newErrorResponse(http.StatusNotFound, "time block not found", c)
+		default:
+			newErrorResponse(http.StatusInternalServerError, "can't delete time block", c)
+		}
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
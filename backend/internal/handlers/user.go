@@ -55,7 +55,6 @@ func (h *Handler) Register(c *gin.Context) {
 		Username:       data.Username,
 		Password:       data.Password,
 		Specialization: data.Specialization,
-		AverageRating:  5,
 	}
 
 	id, err := h.s.Users.Create(c.Request.Context(), user)
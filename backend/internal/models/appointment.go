@@ -5,20 +5,37 @@ import (
 	"time"
 )
 
+const (
+	StatusPending      = "pending"
+	StatusConfirmed    = "confirmed"
+	StatusCanceled     = "canceled"
+	StatusCompleted    = "completed"
+	StatusLateCanceled = "late_canceled"
+	StatusNoShow       = "no_show"
+)
+
+// AppointmentDuration is how long a booked slot lasts.
+const AppointmentDuration = time.Hour
+
 var validStatuses = map[string]bool{
-	"pending":   true,
-	"confirmed": true,
-	"canceled":  true,
-	"completed": true,
+	StatusPending:      true,
+	StatusConfirmed:    true,
+	StatusCanceled:     true,
+	StatusCompleted:    true,
+	StatusLateCanceled: true,
+	StatusNoShow:       true,
 }
 
 type Appointment struct {
-	ID          int       `json:"id"`
-	UserID      int64     `json:"user_id"`
-	MasterID    int64     `json:"master_id"`
-	ScheduledAt time.Time `json:"scheduled_at"`
-	CreatedAt   time.Time `json:"created_at"`
-	Status      string    `json:"status"`
+	ID          int        `json:"id"`
+	UserID      int64      `json:"user_id"`
+	MasterID    int64      `json:"master_id"`
+	ScheduledAt time.Time  `json:"scheduled_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	Status      string     `json:"status"`
+	CanceledAt  *time.Time `json:"canceled_at,omitempty"`
+	CanceledBy  *int64     `json:"canceled_by,omitempty"`
+	SeriesID    *int64     `json:"series_id,omitempty"`
 }
 
 func (a *Appointment) Validate() error {
@@ -43,3 +60,31 @@ func (a *Appointment) Validate() error {
 
 	return nil
 }
+
+func (a *Appointment) IsCanceled() bool {
+	return a.Status == StatusCanceled || a.Status == StatusLateCanceled
+}
+
+// CanTransitionTo checks whether a master may move the appointment into the given status.
+// Completion and no-show marks are only possible once the appointment time has come.
+func (a *Appointment) CanTransitionTo(status string, now time.Time) error {
+	if !validStatuses[status] {
+		return errors.New("invalid status value")
+	}
+	if a.Status != StatusPending && a.Status != StatusConfirmed {
+		return errors.New("appointment status can no longer be changed")
+	}
+	switch status {
+	case StatusConfirmed:
+		if a.Status != StatusPending {
+			return errors.New("appointment is already confirmed")
+		}
+	case StatusCompleted, StatusNoShow:
+		if now.Before(a.ScheduledAt) {
+			return errors.New("appointment has not started yet")
+		}
+	default:
+		return errors.New("status can't be set manually")
+	}
+	return nil
+}
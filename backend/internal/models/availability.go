@@ -0,0 +1,9 @@
+package models
+
+// SlotAlternative is a free slot offered instead of a taken one. Time uses the
+// "2006-01-02 15:04" format accepted by POST /appointments.
+type SlotAlternative struct {
+	MasterID   int64  `json:"master_id"`
+	MasterName string `json:"master_name,omitempty"`
+	Time       string `json:"time"`
+}
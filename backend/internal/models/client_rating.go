@@ -0,0 +1,103 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MaxClientNoteLen = 1000
+
+	// ClientRatingPrior and ClientRatingPriorWeight shrink the scores of
+	// rarely rated clients toward a good default, the same way master
+	// ratings are shrunk.
+	ClientRatingPrior       = 4.0
+	ClientRatingPriorWeight = 3
+	// ClientAttendancePrior is the assumed share of kept appointments of a
+	// client without finished visits.
+	ClientAttendancePrior = 0.9
+)
+
+// Client reliability levels.
+const (
+	ReliabilityUnknown = "unknown"
+	ReliabilityLow     = "low"
+	ReliabilityMedium  = "medium"
+	ReliabilityHigh    = "high"
+)
+
+// ClientRating is a master's private score of a client after a completed
+// appointment. Only masters the client books with can see it.
+type ClientRating struct {
+	Id            int64     `json:"id"`
+	AppointmentId int64     `json:"appointment_id"`
+	MasterId      int64     `json:"master_id"`
+	ClientId      int64     `json:"client_id"`
+	Score         int       `json:"score"`
+	Note          string    `json:"note,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (r *ClientRating) Validate() error {
+	if r.Score < 1 || r.Score > 5 {
+		return errors.New("score must be between 1 and 5")
+	}
+	r.Note = strings.TrimSpace(r.Note)
+	if utf8.RuneCountInString(r.Note) > MaxClientNoteLen {
+		return errors.New("note is too long")
+	}
+	return nil
+}
+
+// ClientReliability sums up how masters rated a client and how the client
+// keeps appointments. Score is 0..100.
+type ClientReliability struct {
+	ClientID     int64          `json:"client_id"`
+	Score        int            `json:"score"`
+	Level        string         `json:"level"`
+	RatingCount  int            `json:"rating_count"`
+	AverageScore float64        `json:"average_score"`
+	Stats        ClientStats    `json:"stats"`
+	Ratings      []ClientRating `json:"ratings"`
+}
+
+// Compute fills Score and Level from Ratings and Stats. Half of the score
+// comes from the ratings, half from the share of finished appointments the
+// client came to without a late cancellation.
+func (r *ClientReliability) Compute() {
+	r.RatingCount = len(r.Ratings)
+	sum := 0
+	for _, cr := range r.Ratings {
+		sum += cr.Score
+	}
+	r.AverageScore = 0
+	if r.RatingCount > 0 {
+		r.AverageScore = round2(float64(sum) / float64(r.RatingCount))
+	}
+
+	kept := r.Stats.Completed
+	finished := kept + r.Stats.NoShows + r.Stats.LateCanceled
+	if r.RatingCount == 0 && finished == 0 {
+		r.Score = 0
+		r.Level = ReliabilityUnknown
+		return
+	}
+
+	mean := (float64(sum) + ClientRatingPrior*ClientRatingPriorWeight) / float64(r.RatingCount+ClientRatingPriorWeight)
+	ratingPart := (mean - 1) / 4
+	attendance := (float64(kept) + ClientAttendancePrior*ClientRatingPriorWeight) / float64(finished+ClientRatingPriorWeight)
+
+	r.Score = int(math.Round(100 * (ratingPart + attendance) / 2))
+	switch {
+	case r.Score >= 80:
+		r.Level = ReliabilityHigh
+	case r.Score >= 50:
+		r.Level = ReliabilityMedium
+	default:
+		r.Level = ReliabilityLow
+	}
+}
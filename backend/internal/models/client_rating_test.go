@@ -0,0 +1,31 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientReliability_Compute(t *testing.T) {
+	good := &ClientReliability{
+		Ratings: []ClientRating{{Score: 5}, {Score: 5}, {Score: 5}},
+		Stats:   ClientStats{Total: 5, Completed: 5},
+	}
+	good.Compute()
+	require.Equal(t, 92, good.Score)
+	require.Equal(t, ReliabilityHigh, good.Level)
+	require.Equal(t, 3, good.RatingCount)
+	require.Equal(t, 5.0, good.AverageScore)
+
+	bad := &ClientReliability{
+		Ratings: []ClientRating{{Score: 1}, {Score: 1}, {Score: 1}},
+		Stats:   ClientStats{Total: 4, NoShows: 4},
+	}
+	bad.Compute()
+	require.Equal(t, 38, bad.Score)
+	require.Equal(t, ReliabilityLow, bad.Level)
+
+	unknown := &ClientReliability{Stats: ClientStats{Total: 1, Canceled: 1}}
+	unknown.Compute()
+	require.Equal(t, ReliabilityUnknown, unknown.Level)
+}
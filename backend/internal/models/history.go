@@ -0,0 +1,76 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	HistoryRoleClient = "client"
+	HistoryRoleMaster = "master"
+
+	SortAsc  = "asc"
+	SortDesc = "desc"
+
+	DefaultHistoryLimit = 20
+	MaxHistoryLimit     = 100
+)
+
+// HistoryFilter narrows the appointment history of one participant. From and
+// To are dates; To is inclusive. CounterpartID is the master for a client's
+// history and the client for a master's one.
+type HistoryFilter struct {
+	Role          string
+	UserID        int64
+	From          *time.Time
+	To            *time.Time
+	Statuses      []string
+	CounterpartID int64
+	Sort          string
+	Limit         int
+	Cursor        string
+}
+
+// HistoryCursor is the keyset position of the last appointment on a page.
+// Sort is the order the position was taken in.
+type HistoryCursor struct {
+	Sort        string    `json:"s"`
+	ScheduledAt time.Time `json:"t"`
+	ID          int       `json:"id"`
+}
+
+type HistoryPage struct {
+	Appointments []Appointment
+	NextCursor   string
+}
+
+// Normalize fills in defaults and validates the filter.
+func (f *HistoryFilter) Normalize() error {
+	if f.Role != HistoryRoleClient && f.Role != HistoryRoleMaster {
+		return errors.New("invalid role")
+	}
+	if f.Sort == "" {
+		f.Sort = SortDesc
+	}
+	if f.Sort != SortAsc && f.Sort != SortDesc {
+		return errors.New("sort must be asc or desc")
+	}
+	if f.Limit == 0 {
+		f.Limit = DefaultHistoryLimit
+	}
+	if f.Limit < 0 || f.Limit > MaxHistoryLimit {
+		return errors.New("limit must be between 1 and 100")
+	}
+	if f.From != nil && f.To != nil && f.To.Before(*f.From) {
+		return errors.New("to must not be before from")
+	}
+	if f.CounterpartID < 0 {
+		return errors.New("counterpart_id must be positive")
+	}
+	for _, s := range f.Statuses {
+		if !validStatuses[s] {
+			return errors.New("invalid status value: " + s)
+		}
+	}
+	return nil
+}
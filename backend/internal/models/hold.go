@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// SlotHold reserves a slot for a client while they finish booking.
+type SlotHold struct {
+	MasterID  int64     `json:"master_id"`
+	UserID    int64     `json:"user_id"`
+	Time      time.Time `json:"time"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,11 @@
+package models
+
+// IdempotencyRecord is a request made with an Idempotency-Key. Until the first
+// request finishes it is pending and has no response.
+type IdempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
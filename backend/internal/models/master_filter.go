@@ -0,0 +1,65 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Master list orders. rating uses the Bayesian score of the rating summary.
+const (
+	MasterSortRating  = "rating"
+	MasterSortReviews = "reviews"
+	MasterSortNewest  = "newest"
+	MasterSortName    = "name"
+
+	DefaultMastersLimit = 20
+	MaxMastersLimit     = 100
+)
+
+var masterSorts = map[string]bool{
+	MasterSortRating:  true,
+	MasterSortReviews: true,
+	MasterSortNewest:  true,
+	MasterSortName:    true,
+}
+
+// MasterFilter narrows the master catalogue. MinRating applies to the plain
+// average rating. FreeOn keeps masters with at least one bookable slot left
+// on that date.
+type MasterFilter struct {
+	Specialization string
+	MinRating      float64
+	FreeOn         *time.Time
+	Sort           string
+	Limit          int
+	Offset         int
+}
+
+// MasterPage is one page of masters with the number of all matching ones.
+type MasterPage struct {
+	Masters []User
+	Total   int
+}
+
+// Normalize fills in defaults and validates the filter.
+func (f *MasterFilter) Normalize() error {
+	if f.Sort == "" {
+		f.Sort = MasterSortRating
+	}
+	if !masterSorts[f.Sort] {
+		return errors.New("sort must be rating, reviews, newest or name")
+	}
+	if f.MinRating < 0 || f.MinRating > 5 {
+		return errors.New("min_rating must be between 0 and 5")
+	}
+	if f.Limit == 0 {
+		f.Limit = DefaultMastersLimit
+	}
+	if f.Limit < 0 || f.Limit > MaxMastersLimit {
+		return errors.New("limit must be between 1 and 100")
+	}
+	if f.Offset < 0 {
+		return errors.New("offset must not be negative")
+	}
+	return nil
+}
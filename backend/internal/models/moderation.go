@@ -0,0 +1,63 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Review moderation states. Only visible reviews are listed publicly and
+// counted in ratings; hidden ones wait for a moderator, removed ones stay
+// out for good.
+const (
+	ReviewVisible = "visible"
+	ReviewHidden  = "hidden"
+	ReviewRemoved = "removed"
+)
+
+// Report states mirror the moderation queue: open until a moderator
+// dismisses the reports or removes the review.
+const (
+	ReportOpen      = "open"
+	ReportDismissed = "dismissed"
+	ReportRemoved   = "removed"
+)
+
+const MaxReportCommentLen = 500
+
+var ReportReasons = map[string]bool{
+	"spam":      true,
+	"offensive": true,
+	"fake":      true,
+	"other":     true,
+}
+
+type ReviewReport struct {
+	Id         int64      `json:"id"`
+	ReviewId   int64      `json:"review_id"`
+	ReporterId int64      `json:"reporter_id"`
+	Reason     string     `json:"reason"`
+	Comment    string     `json:"comment,omitempty"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	ResolvedBy *int64     `json:"resolved_by,omitempty"`
+}
+
+func (r *ReviewReport) Validate() error {
+	if !ReportReasons[r.Reason] {
+		return errors.New("reason must be one of spam, offensive, fake, other")
+	}
+	r.Comment = strings.TrimSpace(r.Comment)
+	if utf8.RuneCountInString(r.Comment) > MaxReportCommentLen {
+		return errors.New("comment is too long")
+	}
+	return nil
+}
+
+// ModerationCase is a reported review together with its reports in one state.
+type ModerationCase struct {
+	Review  Review         `json:"review"`
+	Reports []ReviewReport `json:"reports"`
+}
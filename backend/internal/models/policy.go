@@ -0,0 +1,60 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	DefaultFreeCancelHours = 24
+	DefaultNoShowThreshold = 3
+)
+
+// CancellationPolicy of a master. With AutoConfirm bookings are confirmed
+// right away, otherwise they wait for the master. RequireConfirmation keeps
+// bookings of clients at the no-show threshold pending even with AutoConfirm.
+type CancellationPolicy struct {
+	MasterID            int64 `json:"master_id"`
+	FreeCancelHours     int   `json:"free_cancel_hours"`
+	NoShowThreshold     int   `json:"no_show_threshold"`
+	AutoConfirm         bool  `json:"auto_confirm"`
+	RequireConfirmation bool  `json:"require_confirmation"`
+}
+
+func DefaultCancellationPolicy(masterId int64) *CancellationPolicy {
+	return &CancellationPolicy{
+		MasterID:        masterId,
+		FreeCancelHours: DefaultFreeCancelHours,
+		NoShowThreshold: DefaultNoShowThreshold,
+	}
+}
+
+func (p *CancellationPolicy) Validate() error {
+	if p.FreeCancelHours < 0 || p.FreeCancelHours > 24*14 {
+		return errors.New("free_cancel_hours must be between 0 and 336")
+	}
+	if p.NoShowThreshold < 0 {
+		return errors.New("no_show_threshold must not be negative")
+	}
+	return nil
+}
+
+// IsLateCancel reports whether canceling at now misses the free-cancel deadline.
+func (p *CancellationPolicy) IsLateCancel(scheduledAt, now time.Time) bool {
+	deadline := scheduledAt.Add(-time.Duration(p.FreeCancelHours) * time.Hour)
+	return now.After(deadline)
+}
+
+// NeedsConfirmation reports whether a client with the given stats has to be confirmed manually.
+func (p *CancellationPolicy) NeedsConfirmation(stats *ClientStats) bool {
+	return p.RequireConfirmation && stats != nil && stats.NoShows >= p.NoShowThreshold
+}
+
+type ClientStats struct {
+	ClientID     int64 `json:"client_id"`
+	Total        int   `json:"total"`
+	Completed    int   `json:"completed"`
+	Canceled     int   `json:"canceled"`
+	LateCanceled int   `json:"late_canceled"`
+	NoShows      int   `json:"no_shows"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// RatingPriorWeight is how many virtual reviews at the prior mean are
+	// blended into every master's score, so a handful of reviews can't
+	// outrank a long track record.
+	RatingPriorWeight = 10
+	// DefaultRatingPrior is used as the prior mean until there are any reviews at all.
+	DefaultRatingPrior = 4.0
+	// RatingTrendWindow is the period treated as recent when computing the trend.
+	RatingTrendWindow = 90 * 24 * time.Hour
+)
+
+// RatingSummary aggregates the reviews of one master. Histogram[i] counts
+// reviews with i+1 stars. Score is the Bayesian average used for ranking.
+// Trend is the mean of recent reviews minus the mean of older ones, zero
+// while either period has no reviews; it is only filled on profiles.
+type RatingSummary struct {
+	Count       int     `json:"count"`
+	Histogram   [5]int  `json:"histogram"`
+	Mean        float64 `json:"mean"`
+	Score       float64 `json:"score"`
+	Trend       float64 `json:"trend"`
+	RecentCount int     `json:"recent_count"`
+
+	Sum int `json:"-"`
+}
+
+// Compute derives Mean and Score from the counters.
+func (s *RatingSummary) Compute(priorMean float64) {
+	if priorMean <= 0 {
+		priorMean = DefaultRatingPrior
+	}
+	s.Mean, s.Score = 0, round2(priorMean)
+	if s.Count == 0 {
+		return
+	}
+
+	s.Mean = round2(float64(s.Sum) / float64(s.Count))
+	s.Score = round2((RatingPriorWeight*priorMean + float64(s.Sum)) / float64(RatingPriorWeight+s.Count))
+}
+
+// SetRecent records reviews received within RatingTrendWindow and derives Trend.
+func (s *RatingSummary) SetRecent(count int, sum int) {
+	s.RecentCount, s.Trend = count, 0
+
+	older := s.Count - count
+	if count > 0 && older > 0 {
+		recentMean := float64(sum) / float64(count)
+		olderMean := float64(s.Sum-sum) / float64(older)
+		s.Trend = round2(recentMean - olderMean)
+	}
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
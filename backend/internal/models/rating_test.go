@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRatingSummary_ScoreFavoursTrackRecord(t *testing.T) {
+	single := &RatingSummary{Count: 1, Sum: 5, Histogram: [5]int{0, 0, 0, 0, 1}}
+	single.Compute(4)
+
+	many := &RatingSummary{Count: 200, Sum: 980}
+	many.Compute(4)
+
+	require.Equal(t, 5.0, single.Mean)
+	require.Equal(t, 4.9, many.Mean)
+	require.Greater(t, many.Score, single.Score)
+}
+
+func TestRatingSummary_Trend(t *testing.T) {
+	s := &RatingSummary{Count: 4, Sum: 16}
+	s.SetRecent(2, 6)
+	require.Equal(t, -2.0, s.Trend)
+
+	onlyRecent := &RatingSummary{Count: 2, Sum: 10}
+	onlyRecent.SetRecent(2, 10)
+	require.Zero(t, onlyRecent.Trend)
+}
+
+func TestRatingSummary_NoReviews(t *testing.T) {
+	s := &RatingSummary{}
+	s.Compute(0)
+	require.Zero(t, s.Mean)
+	require.Equal(t, DefaultRatingPrior, s.Score)
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	FrequencyWeekly   = "weekly"
+	FrequencyBiweekly = "biweekly"
+	FrequencyMonthly  = "monthly"
+
+	MaxOccurrences = 52
+)
+
+type Recurrence struct {
+	Frequency string     `json:"frequency"`
+	Count     int        `json:"count,omitempty"`
+	Until     *time.Time `json:"until,omitempty"`
+}
+
+type AppointmentSeries struct {
+	ID        int64      `json:"id"`
+	UserID    int64      `json:"user_id"`
+	MasterID  int64      `json:"master_id"`
+	Frequency string     `json:"frequency"`
+	Count     int        `json:"count,omitempty"`
+	Until     *time.Time `json:"until,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type OccurrenceConflict struct {
+	Time   time.Time `json:"time"`
+	Reason string    `json:"reason"`
+}
+
+// Validate checks the rule for a series starting at start. An until date
+// must not precede the start or allow more than MaxOccurrences.
+func (r *Recurrence) Validate(start time.Time) error {
+	switch r.Frequency {
+	case FrequencyWeekly, FrequencyBiweekly, FrequencyMonthly:
+	default:
+		return errors.New("frequency must be weekly, biweekly or monthly")
+	}
+	if (r.Count == 0) == (r.Until == nil) {
+		return errors.New("exactly one of count or until must be set")
+	}
+	if r.Until == nil && (r.Count < 2 || r.Count > MaxOccurrences) {
+		return errors.New("count must be between 2 and 52")
+	}
+	if r.Until != nil {
+		y, m, d := start.Date()
+		if r.Until.Before(time.Date(y, m, d, 0, 0, 0, 0, r.Until.Location())) {
+			return errors.New("until must not be before the first appointment")
+		}
+		if len(r.expand(start, MaxOccurrences+1)) > MaxOccurrences {
+			return errors.New("until must not allow more than 52 occurrences")
+		}
+	}
+	return nil
+}
+
+// Occurrences expands the rule starting from the first appointment time.
+// Monthly occurrences are clamped to the last day of shorter months,
+// and the result never exceeds MaxOccurrences.
+func (r *Recurrence) Occurrences(start time.Time) []time.Time {
+	return r.expand(start, MaxOccurrences)
+}
+
+// expand lists at most limit occurrences of the rule.
+func (r *Recurrence) expand(start time.Time, limit int) []time.Time {
+	var until time.Time
+	if r.Until != nil {
+		y, m, d := r.Until.Date()
+		until = time.Date(y, m, d, 23, 59, 59, 0, start.Location())
+	}
+
+	var res []time.Time
+	for i := 0; i < limit; i++ {
+		if r.Count > 0 && i >= r.Count {
+			break
+		}
+		t := r.nth(start, i)
+		if r.Until != nil && t.After(until) {
+			break
+		}
+		res = append(res, t)
+	}
+	return res
+}
+
+func (r *Recurrence) nth(start time.Time, i int) time.Time {
+	switch r.Frequency {
+	case FrequencyBiweekly:
+		return start.AddDate(0, 0, 14*i)
+	case FrequencyMonthly:
+		y, m, d := start.Date()
+		first := time.Date(y, m+time.Month(i), 1, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+		if last := first.AddDate(0, 1, -1).Day(); d > last {
+			d = last
+		}
+		return first.AddDate(0, 0, d-1)
+	default:
+		return start.AddDate(0, 0, 7*i)
+	}
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecurrence_BiweeklyCount(t *testing.T) {
+	start := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	r := &Recurrence{Frequency: FrequencyBiweekly, Count: 3}
+
+	require.NoError(t, r.Validate(start))
+	require.Equal(t, []time.Time{
+		start,
+		time.Date(2025, 6, 16, 10, 0, 0, 0, time.UTC),
+		time.Date(2025, 6, 30, 10, 0, 0, 0, time.UTC),
+	}, r.Occurrences(start))
+}
+
+func TestRecurrence_MonthlyClampsToMonthEnd(t *testing.T) {
+	start := time.Date(2025, 1, 31, 9, 30, 0, 0, time.UTC)
+	until := time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC)
+	r := &Recurrence{Frequency: FrequencyMonthly, Until: &until}
+
+	require.Equal(t, []time.Time{
+		start,
+		time.Date(2025, 2, 28, 9, 30, 0, 0, time.UTC),
+		time.Date(2025, 3, 31, 9, 30, 0, 0, time.UTC),
+		time.Date(2025, 4, 30, 9, 30, 0, 0, time.UTC),
+	}, r.Occurrences(start))
+}
+
+func TestRecurrence_Validate(t *testing.T) {
+	start := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	until := start.AddDate(0, 1, 0)
+
+	require.Error(t, (&Recurrence{Frequency: "daily", Count: 3}).Validate(start))
+	require.Error(t, (&Recurrence{Frequency: FrequencyWeekly}).Validate(start))
+	require.Error(t, (&Recurrence{Frequency: FrequencyWeekly, Count: 3, Until: &until}).Validate(start))
+	require.Error(t, (&Recurrence{Frequency: FrequencyWeekly, Count: MaxOccurrences + 1}).Validate(start))
+	require.NoError(t, (&Recurrence{Frequency: FrequencyWeekly, Until: &until}).Validate(start))
+}
+
+func TestRecurrence_ValidateUntil(t *testing.T) {
+	start := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	before := start.AddDate(0, 0, -1)
+	sameDay := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	lastWeekly := start.AddDate(0, 0, 7*(MaxOccurrences-1))
+	tooFar := start.AddDate(0, 0, 7*MaxOccurrences)
+
+	require.Error(t, (&Recurrence{Frequency: FrequencyWeekly, Until: &before}).Validate(start))
+	require.NoError(t, (&Recurrence{Frequency: FrequencyWeekly, Until: &sameDay}).Validate(start))
+	require.NoError(t, (&Recurrence{Frequency: FrequencyWeekly, Until: &lastWeekly}).Validate(start))
+	require.Error(t, (&Recurrence{Frequency: FrequencyWeekly, Until: &tooFar}).Validate(start))
+}
@@ -1,13 +1,97 @@
 package models
 
-import "time"
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// MaxReplyLen limits a master's reply to a review, in characters.
+	MaxReplyLen = 1000
+
+	MaxReviewPhotos    = 5
+	MaxReviewPhotoSize = 5 << 20
+)
+
+// ReviewPhotoTypes are the image formats accepted as review attachments.
+var ReviewPhotoTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
 
 type Review struct {
-	Id        int64     `json:"id"`
-	UserId    int64     `json:"user_id"`
+	Id       int64 `json:"id"`
+	UserId   int64 `json:"user_id"`
+	MasterId int64 `json:"master_id"`
+	// AppointmentId is the completed visit the review is about. Reviews left
+	// before reviews were tied to visits have none.
+	AppointmentId *int64    `json:"appointment_id,omitempty"`
+	Rating        int       `json:"rating"`
+	Comment       string    `json:"comment"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	// EditedAt is when the author last changed the rating or comment. Older
+	// versions are kept as revisions.
+	EditedAt   *time.Time `json:"edited_at,omitempty"`
+	Edited     bool       `json:"edited"`
+	Moderation string     `json:"moderation,omitempty"`
+	// Screening holds the objections of automatic screening. Public
+	// listings leave it out.
+	Screening []ScreeningFinding `json:"screening,omitempty"`
+	// HelpfulCount and UnhelpfulCount are the votes of other users.
+	HelpfulCount   int           `json:"helpful_count"`
+	UnhelpfulCount int           `json:"unhelpful_count"`
+	Reply          *ReviewReply  `json:"reply,omitempty"`
+	Photos         []ReviewPhoto `json:"photos,omitempty"`
+}
+
+// ReviewRevision is a replaced version of a review. WrittenAt is when the
+// version was posted, ReplacedAt when the author edited it.
+type ReviewRevision struct {
+	Id         int64     `json:"id"`
+	ReviewId   int64     `json:"review_id"`
+	Rating     int       `json:"rating"`
+	Comment    string    `json:"comment"`
+	WrittenAt  time.Time `json:"written_at"`
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+// ReviewVote is one user's opinion on whether a review was useful.
+type ReviewVote struct {
+	ReviewId int64 `json:"review_id"`
+	UserId   int64 `json:"user_id"`
+	Helpful  bool  `json:"helpful"`
+}
+
+// ReviewPhoto describes an image attached to a review. The image itself is
+// served by GET /reviews/{review_id}/photos/{id}.
+type ReviewPhoto struct {
+	Id          int64     `json:"id"`
+	ReviewId    int64     `json:"review_id"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ReviewReply is the reviewed master's public answer to a review.
+type ReviewReply struct {
+	ReviewId  int64     `json:"review_id"`
 	MasterId  int64     `json:"master_id"`
-	Rating    int       `json:"rating"`
-	Comment   string    `json:"comment"`
+	Text      string    `json:"text"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+func (r *ReviewReply) Validate() error {
+	r.Text = strings.TrimSpace(r.Text)
+	if r.Text == "" {
+		return errors.New("reply text is required")
+	}
+	if utf8.RuneCountInString(r.Text) > MaxReplyLen {
+		return errors.New("reply is too long")
+	}
+	return nil
+}
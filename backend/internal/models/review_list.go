@@ -0,0 +1,74 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Review list orders. most_helpful counts helpful votes only. Ties are
+// broken by newest first.
+const (
+	ReviewSortNewest      = "newest"
+	ReviewSortHighest     = "highest"
+	ReviewSortLowest      = "lowest"
+	ReviewSortMostHelpful = "most_helpful"
+
+	DefaultReviewLimit = 20
+	MaxReviewLimit     = 100
+)
+
+var reviewSorts = map[string]bool{
+	ReviewSortNewest:      true,
+	ReviewSortHighest:     true,
+	ReviewSortLowest:      true,
+	ReviewSortMostHelpful: true,
+}
+
+// ReviewFilter narrows the public reviews of one master. Ratings keeps only
+// reviews with one of the given star counts.
+type ReviewFilter struct {
+	MasterID    int64
+	Ratings     []int
+	WithPhotos  bool
+	WithComment bool
+	Sort        string
+	Limit       int
+	Cursor      string
+}
+
+// ReviewCursor is the keyset position of the last review on a page. Sort is
+// kept so that a cursor can't be replayed against another order.
+type ReviewCursor struct {
+	Sort      string    `json:"s"`
+	Rating    int       `json:"r"`
+	Helpful   int       `json:"h,omitempty"`
+	CreatedAt time.Time `json:"t"`
+	ID        int64     `json:"id"`
+}
+
+type ReviewPage struct {
+	Reviews    []Review
+	NextCursor string
+}
+
+// Normalize fills in defaults and validates the filter.
+func (f *ReviewFilter) Normalize() error {
+	if f.Sort == "" {
+		f.Sort = ReviewSortNewest
+	}
+	if !reviewSorts[f.Sort] {
+		return errors.New("sort must be newest, highest, lowest or most_helpful")
+	}
+	if f.Limit == 0 {
+		f.Limit = DefaultReviewLimit
+	}
+	if f.Limit < 0 || f.Limit > MaxReviewLimit {
+		return errors.New("limit must be between 1 and 100")
+	}
+	for _, r := range f.Ratings {
+		if r < 1 || r > 5 {
+			return errors.New("rating must be between 1 and 5")
+		}
+	}
+	return nil
+}
@@ -1,7 +1,94 @@
 package models
 
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	BlockSourceManual = "manual"
+	BlockSourceImport = "ics"
+
+	MaxBlockDuration  = 31 * 24 * time.Hour
+	MaxBlockReasonLen = 200
+	// MaxImportedBlocks caps busy times taken from one calendar import.
+	MaxImportedBlocks = 5000
+)
+
+var WeekDays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+// WeeklyTemplate holds working slots per day of week used from EffectiveFrom (YYYY-MM-DD)
+// until the next template takes effect. Days without slots are days off.
+type WeeklyTemplate struct {
+	EffectiveFrom string              `json:"effective_from"`
+	Days          map[string][]string `json:"days"`
+	CreatedAt     time.Time           `json:"created_at"`
+}
+
+// DateSlots overrides the template's slots on a single date (YYYY-MM-DD).
+type DateSlots struct {
+	Date  string   `json:"date"`
+	Slots []string `json:"slots"`
+}
+
+// TemplateChange is a saved template with booked appointments that don't fit it anymore.
+type TemplateChange struct {
+	Template  *WeeklyTemplate `json:"template"`
+	Dates     []DateSlots     `json:"dates,omitempty"`
+	Conflicts []Appointment   `json:"conflicts"`
+}
+
 type TodaySchedule struct {
-	DaysOff      []string `json:"days_off"`
-	Slots        []string `json:"slots"`
-	Appointments []string `json:"appointments"`
+	DaysOff      []string        `json:"days_off"`
+	Slots        []string        `json:"slots"`
+	Appointments []string        `json:"appointments"`
+	Blocks       []ScheduleBlock `json:"blocks"`
+	Held         []string        `json:"held"`
+}
+
+// ScheduleBlock is a part of a TimeBlock falling on the requested day.
+// Reasons of imported blocks come from private calendars and are not exposed.
+type ScheduleBlock struct {
+	ID     int64  `json:"id"`
+	Start  string `json:"start"`
+	End    string `json:"end"`
+	Reason string `json:"reason,omitempty"`
+	Source string `json:"source"`
+}
+
+// TimeBlock is an interval when the master can't be booked regardless of working slots.
+type TimeBlock struct {
+	ID       int64     `json:"id"`
+	MasterID int64     `json:"master_id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   string    `json:"reason,omitempty"`
+	Source   string    `json:"source"`
+}
+
+func (b *TimeBlock) Validate() error {
+	if !b.EndsAt.After(b.StartsAt) {
+		return errors.New("block must end after it starts")
+	}
+	if b.EndsAt.Sub(b.StartsAt) > MaxBlockDuration {
+		return errors.New("block can't be longer than 31 days, use days off instead")
+	}
+	if utf8.RuneCountInString(b.Reason) > MaxBlockReasonLen {
+		return fmt.Errorf("reason can't be longer than %d characters", MaxBlockReasonLen)
+	}
+	return nil
+}
+
+// Overlaps reports whether the block intersects [from, to).
+func (b *TimeBlock) Overlaps(from, to time.Time) bool {
+	return b.StartsAt.Before(to) && b.EndsAt.After(from)
+}
+
+type CalendarImport struct {
+	MasterID   int64      `json:"master_id"`
+	URL        string     `json:"url,omitempty"`
+	ImportedAt *time.Time `json:"imported_at,omitempty"`
+	Imported   int        `json:"imported"`
 }
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeBlock_Validate(t *testing.T) {
+	start := time.Date(2025, 6, 2, 14, 0, 0, 0, time.UTC)
+
+	require.NoError(t, (&TimeBlock{StartsAt: start, EndsAt: start.Add(90 * time.Minute), Reason: "Стоматолог"}).Validate())
+	require.Error(t, (&TimeBlock{StartsAt: start, EndsAt: start}).Validate())
+	require.Error(t, (&TimeBlock{StartsAt: start, EndsAt: start.Add(-time.Hour)}).Validate())
+	require.Error(t, (&TimeBlock{StartsAt: start, EndsAt: start.Add(MaxBlockDuration + time.Hour)}).Validate())
+	require.Error(t, (&TimeBlock{
+		StartsAt: start,
+		EndsAt:   start.Add(time.Hour),
+		Reason:   strings.Repeat("я", MaxBlockReasonLen+1),
+	}).Validate())
+}
+
+func TestTimeBlock_Overlaps(t *testing.T) {
+	start := time.Date(2025, 6, 2, 14, 0, 0, 0, time.UTC)
+	b := TimeBlock{StartsAt: start, EndsAt: start.Add(90 * time.Minute)}
+
+	require.True(t, b.Overlaps(start.Add(-30*time.Minute), start.Add(30*time.Minute)))
+	require.True(t, b.Overlaps(start.Add(time.Hour), start.Add(2*time.Hour)))
+	require.False(t, b.Overlaps(start.Add(-time.Hour), start))
+	require.False(t, b.Overlaps(start.Add(90*time.Minute), start.Add(150*time.Minute)))
+}
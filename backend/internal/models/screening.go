@@ -0,0 +1,69 @@
+package models
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+	"unicode/utf8"
+)
+
+// Screening verdicts, from the mildest. A review gets the strongest verdict
+// of all screening checks.
+const (
+	ScreeningAccept = "accept"
+	ScreeningHide   = "hide"
+	ScreeningReject = "reject"
+)
+
+// MinFingerprintLen is the shortest comment, in characters, that is checked
+// for copies in other reviews. Short praise like "Всё отлично!" repeats
+// naturally.
+const MinFingerprintLen = 20
+
+var screeningRank = map[string]int{
+	ScreeningAccept: 0,
+	ScreeningHide:   1,
+	ScreeningReject: 2,
+}
+
+// ScreeningFinding is one reason a screening check objected to a review.
+type ScreeningFinding struct {
+	Check   string `json:"check"`
+	Verdict string `json:"verdict"`
+	Reason  string `json:"reason"`
+}
+
+type ScreeningResult struct {
+	Verdict  string
+	Findings []ScreeningFinding
+}
+
+// Add records a finding and raises the verdict if the finding is stronger.
+func (r *ScreeningResult) Add(f ScreeningFinding) {
+	r.Findings = append(r.Findings, f)
+	if screeningRank[f.Verdict] > screeningRank[r.Verdict] {
+		r.Verdict = f.Verdict
+	}
+}
+
+// Reasons lists the reasons of findings with the given verdict.
+func (r *ScreeningResult) Reasons(verdict string) []string {
+	var reasons []string
+	for _, f := range r.Findings {
+		if f.Verdict == verdict {
+			reasons = append(reasons, f.Reason)
+		}
+	}
+	return reasons
+}
+
+// CommentFingerprint identifies a comment regardless of case and spacing.
+// Comments shorter than MinFingerprintLen have no fingerprint.
+func CommentFingerprint(comment string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(comment), " "))
+	if utf8.RuneCountInString(normalized) < MinFingerprintLen {
+		return ""
+	}
+	sum := sha1.Sum([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScreeningResult_StrongestVerdictWins(t *testing.T) {
+	res := ScreeningResult{Verdict: ScreeningAccept}
+
+	res.Add(ScreeningFinding{Check: "contacts", Verdict: ScreeningHide, Reason: "contains a link"})
+	res.Add(ScreeningFinding{Check: "words", Verdict: ScreeningReject, Reason: "contains a banned word"})
+	res.Add(ScreeningFinding{Check: "duplicates", Verdict: ScreeningHide, Reason: "copied text"})
+
+	require.Equal(t, ScreeningReject, res.Verdict)
+	require.Equal(t, []string{"contains a banned word"}, res.Reasons(ScreeningReject))
+	require.Len(t, res.Findings, 3)
+}
+
+func TestCommentFingerprint(t *testing.T) {
+	a := CommentFingerprint("Лучший мастер в городе,  всем   советую!")
+	b := CommentFingerprint("лучший мастер в городе, всем советую!\n")
+
+	require.NotEmpty(t, a)
+	require.Equal(t, a, b)
+	require.Empty(t, CommentFingerprint("Всё отлично!"))
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+	MaxSearchQueryLen  = 100
+
+	// SearchFuzzyThreshold is the least trigram word similarity for a
+	// misspelled query to still match.
+	SearchFuzzyThreshold = 0.4
+	// SearchRatingWeight is the share of the master's rating score in the
+	// search order; the rest is text relevance.
+	SearchRatingWeight = 0.3
+)
+
+// SearchQuery is a full-text search for masters. Pages are addressed by an
+// opaque cursor.
+type SearchQuery struct {
+	Text   string
+	Limit  int
+	Cursor string
+}
+
+// SearchCursor is the position of the next page in the ranked results.
+type SearchCursor struct {
+	Offset int `json:"o"`
+}
+
+type SearchPage struct {
+	Masters    []User
+	NextCursor string
+}
+
+// Normalize fills in defaults and validates the query.
+func (q *SearchQuery) Normalize() error {
+	q.Text = strings.Join(strings.Fields(q.Text), " ")
+	if q.Text == "" {
+		return errors.New("search query is required")
+	}
+	if utf8.RuneCountInString(q.Text) > MaxSearchQueryLen {
+		return errors.New("search query is too long")
+	}
+	if q.Limit == 0 {
+		q.Limit = DefaultSearchLimit
+	}
+	if q.Limit < 0 || q.Limit > MaxSearchLimit {
+		return errors.New("limit must be between 1 and 100")
+	}
+	return nil
+}
+
+// Suggestion kinds. A master suggestion links to the profile by username; a
+// specialization one to the catalogue filtered by it.
+const (
+	SuggestionKindMaster         = "master"
+	SuggestionKindSpecialization = "specialization"
+
+	DefaultSuggestLimit = 8
+	MaxSuggestLimit     = 20
+	MaxSuggestPrefixLen = 50
+)
+
+// Suggestion is one autocomplete entry for the search bar.
+type Suggestion struct {
+	Kind     string `json:"kind"`
+	Text     string `json:"text"`
+	Username string `json:"username,omitempty"`
+}
+
+// SuggestQuery asks for suggestions starting with Prefix.
+type SuggestQuery struct {
+	Prefix string
+	Limit  int
+}
+
+// Normalize lowercases the prefix, fills in defaults and validates the query.
+func (q *SuggestQuery) Normalize() error {
+	q.Prefix = strings.ToLower(strings.Join(strings.Fields(q.Prefix), " "))
+	if q.Prefix == "" {
+		return errors.New("prefix is required")
+	}
+	if utf8.RuneCountInString(q.Prefix) > MaxSuggestPrefixLen {
+		return errors.New("prefix is too long")
+	}
+	if q.Limit == 0 {
+		q.Limit = DefaultSuggestLimit
+	}
+	if q.Limit < 0 || q.Limit > MaxSuggestLimit {
+		return errors.New("limit must be between 1 and 20")
+	}
+	return nil
+}
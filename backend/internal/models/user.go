@@ -17,6 +17,14 @@ type User struct {
 	RegisteredAt   time.Time `json:"registered_at"`
 	AverageRating  float64   `json:"average_rating"`
 	Specialization string    `json:"specialization"`
+	// Rating is filled for masters only.
+	Rating *RatingSummary `json:"rating,omitempty"`
+}
+
+// IsMaster reports whether the user provides services, i.e. has a
+// specialization other than the plain "user" one.
+func (u *User) IsMaster() bool {
+	return u.Specialization != "" && u.Specialization != "user"
 }
 
 func (u *User) Validate() error {
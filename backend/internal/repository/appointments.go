@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -14,6 +15,8 @@ import (
 	"strawberry/internal/models"
 )
 
+const appointmentColumns = `id, user_id, master_id, scheduled_at, created_at, status, canceled_at, canceled_by, series_id`
+
 type postgresAppointmentsRepository struct {
 	db *pgxpool.Pool
 }
@@ -48,6 +51,114 @@ func (r *postgresAppointmentsRepository) Create(ctx context.Context, a *models.A
 	return id, nil
 }
 
+// CheckAvailability reports ErrMasterUnavailable or ErrAppointmentConflict
+// when the master can't be booked at the given time.
+func (r *postgresAppointmentsRepository) CheckAvailability(ctx context.Context, masterID int64, scheduledAt time.Time) error {
+	if unavailable, err := r.isMasterUnavailable(ctx, masterID, scheduledAt); err != nil {
+		return err
+	} else if unavailable {
+		return ErrMasterUnavailable
+	}
+
+	const busyQuery = `
+		SELECT COUNT(*) FROM appointments
+		WHERE master_id = $1 AND scheduled_at = $2 AND status NOT IN ('canceled', 'late_canceled')
+	`
+	if count, err := r.countQuery(ctx, busyQuery, masterID, scheduledAt); err != nil {
+		return err
+	} else if count > 0 {
+		return ErrAppointmentConflict
+	}
+	return nil
+}
+
+func (r *postgresAppointmentsRepository) CreateSeries(ctx context.Context, series *models.AppointmentSeries, apts []models.Appointment) ([]int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var until *string
+	if series.Until != nil {
+		u := series.Until.Format("2006-01-02")
+		until = &u
+	}
+	var count *int
+	if series.Count > 0 {
+		count = &series.Count
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO appointment_series (user_id, master_id, frequency, occurrences, until)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at;
+	`, series.UserID, series.MasterID, series.Frequency, count, until).Scan(&series.ID, &series.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(apts))
+	for _, a := range apts {
+		var id int64
+		err := tx.QueryRow(ctx, `
+			INSERT INTO appointments (user_id, master_id, scheduled_at, status, series_id)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id;
+		`, a.UserID, a.MasterID, a.ScheduledAt, a.Status, series.ID).Scan(&id)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+				return nil, ErrAppointmentConflict
+			}
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *postgresAppointmentsRepository) GetSeriesById(ctx context.Context, id int64) (*models.AppointmentSeries, error) {
+	var (
+		s     models.AppointmentSeries
+		count *int
+	)
+	err := r.db.QueryRow(ctx, `
+		SELECT id, user_id, master_id, frequency, occurrences, until, created_at
+		FROM appointment_series
+		WHERE id = $1;
+	`, id).Scan(&s.ID, &s.UserID, &s.MasterID, &s.Frequency, &count, &s.Until, &s.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNoAppointments
+		}
+		return nil, err
+	}
+	if count != nil {
+		s.Count = *count
+	}
+	return &s, nil
+}
+
+func (r *postgresAppointmentsRepository) GetBySeriesId(ctx context.Context, seriesId int64) ([]models.Appointment, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+appointmentColumns+`
+		FROM appointments
+		WHERE series_id = $1
+		ORDER BY scheduled_at;
+	`, seriesId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAppointments(rows)
+}
+
 func (r *postgresAppointmentsRepository) isMasterUnavailable(ctx context.Context, masterID int64, scheduledAt time.Time) (bool, error) {
 	date := scheduledAt.Format("2006-01-02")
 	timeOfDay := scheduledAt.Format("15:04:05")
@@ -72,8 +183,13 @@ func (r *postgresAppointmentsRepository) isMasterUnavailable(ctx context.Context
 		slotQuery = `SELECT COUNT(*) FROM date_slots WHERE user_id = $1 AND date = $2 AND slot = $3`
 		args = []interface{}{masterID, date, timeOfDay}
 	} else {
-		slotQuery = `SELECT COUNT(*) FROM schedule_slots WHERE user_id = $1 AND day_of_week = $2 AND slot = $3`
-		args = []interface{}{masterID, dayOfWeek, timeOfDay}
+		slotQuery = `
+			SELECT COUNT(*) FROM schedule_slots
+			WHERE user_id = $1 AND day_of_week = $2 AND slot = $3 AND effective_from = (
+				SELECT MAX(effective_from) FROM schedule_templates
+				WHERE user_id = $1 AND effective_from <= $4
+			)`
+		args = []interface{}{masterID, dayOfWeek, timeOfDay, date}
 	}
 
 	if available, err := r.countQuery(ctx, slotQuery, args...); err != nil {
@@ -82,6 +198,16 @@ func (r *postgresAppointmentsRepository) isMasterUnavailable(ctx context.Context
 		return true, nil
 	}
 
+	const blockQuery = `
+		SELECT COUNT(*) FROM time_blocks
+		WHERE master_id = $1 AND starts_at < $3 AND ends_at > $2
+	`
+	if blocked, err := r.countQuery(ctx, blockQuery, masterID, scheduledAt, scheduledAt.Add(models.AppointmentDuration)); err != nil {
+		return false, err
+	} else if blocked > 0 {
+		return true, nil
+	}
+
 	return false, nil
 }
 
@@ -104,65 +230,154 @@ func (r *postgresAppointmentsRepository) Delete(ctx context.Context, id int64) e
 
 func (r *postgresAppointmentsRepository) GetByUserId(ctx context.Context, id int64) ([]models.Appointment, error) {
 	rows, err := r.db.Query(ctx, `
-		SELECT id, user_id, master_id, scheduled_at, created_at, status
+		SELECT `+appointmentColumns+`
 		FROM appointments 
-		WHERE user_id = $1 AND scheduled_at >= NOW();
+		WHERE user_id = $1 AND scheduled_at >= NOW() AND status NOT IN ('canceled', 'late_canceled');
 	`, id)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var apts []models.Appointment
-	for rows.Next() {
-		var a models.Appointment
-		if err := rows.Scan(&a.ID, &a.UserID, &a.MasterID, &a.ScheduledAt, &a.CreatedAt, &a.Status); err != nil {
-			return nil, err
-		}
-		apts = append(apts, a)
+	apts, err := scanAppointments(rows)
+	if err != nil {
+		return nil, err
 	}
 	return apts, nil
 }
 
 func (r *postgresAppointmentsRepository) GetByMasterId(ctx context.Context, id int64) ([]models.Appointment, error) {
 	rows, err := r.db.Query(ctx, `
-		SELECT id, user_id, master_id, scheduled_at, created_at, status
+		SELECT `+appointmentColumns+`
 		FROM appointments 
-		WHERE master_id = $1 AND scheduled_at >= NOW();
+		WHERE master_id = $1 AND scheduled_at >= NOW() AND status NOT IN ('canceled', 'late_canceled');
 	`, id)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var apts []models.Appointment
-	for rows.Next() {
-		var a models.Appointment
-		if err := rows.Scan(&a.ID, &a.UserID, &a.MasterID, &a.ScheduledAt, &a.CreatedAt, &a.Status); err != nil {
-			return nil, err
-		}
-		apts = append(apts, a)
+	apts, err := scanAppointments(rows)
+	if err != nil {
+		return nil, err
 	}
 	return apts, nil
 }
+
+// GetByUserIdSince returns the client's appointments in any status starting from since.
+func (r *postgresAppointmentsRepository) GetByUserIdSince(ctx context.Context, id int64, since time.Time) ([]models.Appointment, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+appointmentColumns+`
+		FROM appointments
+		WHERE user_id = $1 AND scheduled_at >= $2
+		ORDER BY scheduled_at;
+	`, id, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAppointments(rows)
+}
+
+// GetByMasterIdSince returns the master's appointments in any status starting from since.
+func (r *postgresAppointmentsRepository) GetByMasterIdSince(ctx context.Context, id int64, since time.Time) ([]models.Appointment, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+appointmentColumns+`
+		FROM appointments
+		WHERE master_id = $1 AND scheduled_at >= $2
+		ORDER BY scheduled_at;
+	`, id, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAppointments(rows)
+}
+
+// History returns up to f.Limit appointments of the participant in any
+// status, ordered by (scheduled_at, id) and starting after the given position.
+func (r *postgresAppointmentsRepository) History(ctx context.Context, f models.HistoryFilter, after *models.HistoryCursor) ([]models.Appointment, error) {
+	own, counterpart := "user_id", "master_id"
+	if f.Role == models.HistoryRoleMaster {
+		own, counterpart = "master_id", "user_id"
+	}
+
+	args := []any{f.UserID}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	where := []string{own + " = $1"}
+	if f.From != nil {
+		where = append(where, "scheduled_at >= "+arg(f.From.Format("2006-01-02")))
+	}
+	if f.To != nil {
+		where = append(where, "scheduled_at < "+arg(f.To.AddDate(0, 0, 1).Format("2006-01-02")))
+	}
+	if len(f.Statuses) > 0 {
+		where = append(where, "status = ANY("+arg(f.Statuses)+")")
+	}
+	if f.CounterpartID > 0 {
+		where = append(where, counterpart+" = "+arg(f.CounterpartID))
+	}
+
+	cmp, order := "<", "DESC"
+	if f.Sort == models.SortAsc {
+		cmp, order = ">", "ASC"
+	}
+	if after != nil {
+		where = append(where, fmt.Sprintf("(scheduled_at, id) %s (%s, %s)", cmp, arg(after.ScheduledAt), arg(after.ID)))
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT `+appointmentColumns+`
+		FROM appointments
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY scheduled_at `+order+`, id `+order+`
+		LIMIT `+arg(f.Limit)+`;
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAppointments(rows)
+}
+
+// GetAwaitingReview returns the client's completed appointments that have no review yet.
+func (r *postgresAppointmentsRepository) GetAwaitingReview(ctx context.Context, userId int64) ([]models.Appointment, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+appointmentColumns+`
+		FROM appointments a
+		WHERE a.user_id = $1 AND a.status = 'completed'
+			AND NOT EXISTS (SELECT 1 FROM reviews r WHERE r.appointment_id = a.id)
+		ORDER BY a.scheduled_at DESC;
+	`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAppointments(rows)
+}
+
 func (r *postgresAppointmentsRepository) GetByDate(ctx context.Context, id int64, date time.Time) ([]models.Appointment, error) {
 	rows, err := r.db.Query(ctx, `
-		SELECT id, user_id, master_id, scheduled_at, created_at, status
+		SELECT `+appointmentColumns+`
 		FROM appointments 
-		WHERE master_id = $1 AND DATE(scheduled_at) = $2;
+		WHERE master_id = $1 AND DATE(scheduled_at) = $2 AND status NOT IN ('canceled', 'late_canceled');
 	`, id, date.Format("2006-01-02"))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var apts []models.Appointment
-	for rows.Next() {
-		var a models.Appointment
-		if err := rows.Scan(&a.ID, &a.UserID, &a.MasterID, &a.ScheduledAt, &a.CreatedAt, &a.Status); err != nil {
-			return nil, err
-		}
-		apts = append(apts, a)
+	apts, err := scanAppointments(rows)
+	if err != nil {
+		return nil, err
 	}
 	if len(apts) == 0 {
 		return nil, ErrNoAppointments
@@ -172,7 +387,7 @@ func (r *postgresAppointmentsRepository) GetByDate(ctx context.Context, id int64
 
 func (r *postgresAppointmentsRepository) GetByStatus(ctx context.Context, status string) ([]models.Appointment, error) {
 	rows, err := r.db.Query(ctx, `
-		SELECT id, user_id, master_id, scheduled_at, created_at, status
+		SELECT `+appointmentColumns+`
 		FROM appointments WHERE status = $1;
 	`, status)
 	if err != nil {
@@ -180,13 +395,9 @@ func (r *postgresAppointmentsRepository) GetByStatus(ctx context.Context, status
 	}
 	defer rows.Close()
 
-	var apts []models.Appointment
-	for rows.Next() {
-		var a models.Appointment
-		if err := rows.Scan(&a.ID, &a.UserID, &a.MasterID, &a.ScheduledAt, &a.CreatedAt, &a.Status); err != nil {
-			return nil, err
-		}
-		apts = append(apts, a)
+	apts, err := scanAppointments(rows)
+	if err != nil {
+		return nil, err
 	}
 	if len(apts) == 0 {
 		return nil, ErrNoAppointments
@@ -196,24 +407,90 @@ func (r *postgresAppointmentsRepository) GetByStatus(ctx context.Context, status
 
 func (r *postgresAppointmentsRepository) GetById(ctx context.Context, id int64) (*models.Appointment, error) {
 	query := `
-		SELECT id, user_id, master_id, scheduled_at, created_at, status
+		SELECT ` + appointmentColumns + `
 		FROM appointments
 		WHERE id = $1;
 	`
-	var a models.Appointment
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&a.ID,
-		&a.UserID,
-		&a.MasterID,
-		&a.ScheduledAt,
-		&a.CreatedAt,
-		&a.Status,
-	)
+	a, err := scanAppointment(r.db.QueryRow(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNoAppointments
 		}
 		return nil, err
 	}
+	return a, nil
+}
+
+func (r *postgresAppointmentsRepository) UpdateStatus(ctx context.Context, id int64, status string) error {
+	cmdTag, err := r.db.Exec(ctx, "UPDATE appointments SET status = $1 WHERE id = $2;", status, id)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrNoAppointments
+	}
+	return nil
+}
+
+func (r *postgresAppointmentsRepository) Cancel(ctx context.Context, id int64, status string, canceledBy int64) error {
+	cmdTag, err := r.db.Exec(ctx, `
+		UPDATE appointments
+		SET status = $1, canceled_at = NOW(), canceled_by = $2
+		WHERE id = $3 AND status NOT IN ('canceled', 'late_canceled');
+	`, status, canceledBy, id)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrNoAppointments
+	}
+	return nil
+}
+
+func (r *postgresAppointmentsRepository) GetClientStats(ctx context.Context, clientId int64) (*models.ClientStats, error) {
+	stats := models.ClientStats{ClientID: clientId}
+	err := r.db.QueryRow(ctx, `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = 'completed'),
+			COUNT(*) FILTER (WHERE status = 'canceled'),
+			COUNT(*) FILTER (WHERE status = 'late_canceled'),
+			COUNT(*) FILTER (WHERE status = 'no_show')
+		FROM appointments
+		WHERE user_id = $1;
+	`, clientId).Scan(&stats.Total, &stats.Completed, &stats.Canceled, &stats.LateCanceled, &stats.NoShows)
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// HasBooked reports whether the client has ever booked with the master.
+func (r *postgresAppointmentsRepository) HasBooked(ctx context.Context, clientId, masterId int64) (bool, error) {
+	var booked bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM appointments WHERE user_id = $1 AND master_id = $2);
+	`, clientId, masterId).Scan(&booked)
+	return booked, err
+}
+
+func scanAppointment(row pgx.Row) (*models.Appointment, error) {
+	var a models.Appointment
+	err := row.Scan(&a.ID, &a.UserID, &a.MasterID, &a.ScheduledAt, &a.CreatedAt, &a.Status, &a.CanceledAt, &a.CanceledBy, &a.SeriesID)
+	if err != nil {
+		return nil, err
+	}
 	return &a, nil
 }
+
+func scanAppointments(rows pgx.Rows) ([]models.Appointment, error) {
+	var apts []models.Appointment
+	for rows.Next() {
+		a, err := scanAppointment(rows)
+		if err != nil {
+			return nil, err
+		}
+		apts = append(apts, *a)
+	}
+	return apts, rows.Err()
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresCalendarFeedsRepository struct {
+	db *pgxpool.Pool
+}
+
+func newPostgresCalendarFeedsRepository(db *pgxpool.Pool) CalendarFeeds {
+	return &postgresCalendarFeedsRepository{db: db}
+}
+
+func (r *postgresCalendarFeedsRepository) GetToken(ctx context.Context, userId int64) (string, error) {
+	var token string
+	err := r.db.QueryRow(ctx, `SELECT token FROM calendar_feeds WHERE user_id = $1;`, userId).Scan(&token)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	return token, nil
+}
+
+func (r *postgresCalendarFeedsRepository) SetToken(ctx context.Context, userId int64, token string) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO calendar_feeds (user_id, token)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET token = EXCLUDED.token, created_at = CURRENT_TIMESTAMP;
+	`, userId, token)
+	return err
+}
+
+func (r *postgresCalendarFeedsRepository) GetUserIdByToken(ctx context.Context, token string) (int64, error) {
+	var userId int64
+	err := r.db.QueryRow(ctx, `SELECT user_id FROM calendar_feeds WHERE token = $1;`, token).Scan(&userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrNotFound
+		}
+		return 0, err
+	}
+	return userId, nil
+}
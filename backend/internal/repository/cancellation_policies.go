@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"strawberry/internal/models"
+)
+
+type postgresCancellationPoliciesRepository struct {
+	db *pgxpool.Pool
+}
+
+func newPostgresCancellationPoliciesRepository(db *pgxpool.Pool) CancellationPolicies {
+	return &postgresCancellationPoliciesRepository{db: db}
+}
+
+func (r *postgresCancellationPoliciesRepository) Get(ctx context.Context, masterId int64) (*models.CancellationPolicy, error) {
+	const query = `
+		SELECT master_id, free_cancel_hours, no_show_threshold, auto_confirm, require_confirmation
+		FROM cancellation_policies
+		WHERE master_id = $1;
+	`
+	var p models.CancellationPolicy
+	err := r.db.QueryRow(ctx, query, masterId).Scan(&p.MasterID, &p.FreeCancelHours, &p.NoShowThreshold, &p.AutoConfirm, &p.RequireConfirmation)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *postgresCancellationPoliciesRepository) Upsert(ctx context.Context, p *models.CancellationPolicy) error {
+	const query = `
+		INSERT INTO cancellation_policies (master_id, free_cancel_hours, no_show_threshold, auto_confirm, require_confirmation)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (master_id) DO UPDATE
+		SET free_cancel_hours = EXCLUDED.free_cancel_hours,
+			no_show_threshold = EXCLUDED.no_show_threshold,
+			auto_confirm = EXCLUDED.auto_confirm,
+			require_confirmation = EXCLUDED.require_confirmation,
+			updated_at = CURRENT_TIMESTAMP;
+	`
+	_, err := r.db.Exec(ctx, query, p.MasterID, p.FreeCancelHours, p.NoShowThreshold, p.AutoConfirm, p.RequireConfirmation)
+	return err
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"strawberry/internal/models"
+)
+
+type postgresClientRatingsRepository struct {
+	db *pgxpool.Pool
+}
+
+func newPostgresClientRatingsRepository(db *pgxpool.Pool) ClientRatings {
+	return &postgresClientRatingsRepository{db: db}
+}
+
+// Upsert saves the master's rating of an appointment's client, replacing an
+// earlier rating of the same appointment.
+func (r *postgresClientRatingsRepository) Upsert(ctx context.Context, cr *models.ClientRating) error {
+	const query = `
+		INSERT INTO client_ratings (appointment_id, master_id, client_id, score, note)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (appointment_id) DO UPDATE
+		SET score = EXCLUDED.score, note = EXCLUDED.note, updated_at = NOW()
+		RETURNING id, created_at, updated_at;
+	`
+	return r.db.QueryRow(ctx, query, cr.AppointmentId, cr.MasterId, cr.ClientId, cr.Score, cr.Note).
+		Scan(&cr.Id, &cr.CreatedAt, &cr.UpdatedAt)
+}
+
+// GetByClient returns all masters' ratings of the client, newest first.
+func (r *postgresClientRatingsRepository) GetByClient(ctx context.Context, clientId int64) ([]models.ClientRating, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, appointment_id, master_id, client_id, score, note, created_at, updated_at
+		FROM client_ratings
+		WHERE client_id = $1
+		ORDER BY created_at DESC;
+	`, clientId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ratings := []models.ClientRating{}
+	for rows.Next() {
+		var cr models.ClientRating
+		if err := rows.Scan(&cr.Id, &cr.AppointmentId, &cr.MasterId, &cr.ClientId, &cr.Score, &cr.Note, &cr.CreatedAt, &cr.UpdatedAt); err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, cr)
+	}
+	return ratings, rows.Err()
+}
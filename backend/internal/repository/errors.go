@@ -7,6 +7,6 @@ var (
 	ErrNoUsers             = errors.New("no users found")
 	ErrNoAppointments      = errors.New("no appointments found")
 	ErrAppointmentConflict = errors.New("appointment conflict")
-	ErrMasterUnavailable = errors.New("master is not available at the selected time")
-	ErrNoWorkingSlots = errors.New("no new working slots")
+	ErrMasterUnavailable   = errors.New("master is not available at the selected time")
+	ErrNoWorkingSlots      = errors.New("no new working slots")
 )
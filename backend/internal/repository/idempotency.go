@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strawberry/internal/models"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+type IdempotencyRepo struct {
+	redis *redis.Client
+}
+
+func newRedisIdempotencyRepo(redis *redis.Client) Idempotency {
+	return &IdempotencyRepo{redis: redis}
+}
+
+func idempotencyKey(userId int64, key string) string {
+	return fmt.Sprintf("idem:%d:%s", userId, key)
+}
+
+// Reserve stores a pending record unless the key is already used. It returns false if it is.
+func (r *IdempotencyRepo) Reserve(ctx context.Context, userId int64, key string, rec *models.IdempotencyRecord, ttl time.Duration) (bool, error) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return false, err
+	}
+	return r.redis.SetNX(idempotencyKey(userId, key), data, ttl).Result()
+}
+
+func (r *IdempotencyRepo) Get(ctx context.Context, userId int64, key string) (*models.IdempotencyRecord, error) {
+	data, err := r.redis.Get(idempotencyKey(userId, key)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	var rec models.IdempotencyRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (r *IdempotencyRepo) Save(ctx context.Context, userId int64, key string, rec *models.IdempotencyRecord, ttl time.Duration) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return r.redis.Set(idempotencyKey(userId, key), data, ttl).Err()
+}
+
+func (r *IdempotencyRepo) Delete(ctx context.Context, userId int64, key string) error {
+	return r.redis.Del(idempotencyKey(userId, key)).Err()
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ratingDelta is a change to the denormalized counters in master_ratings.
+type ratingDelta struct {
+	count int
+	sum   int
+	stars [5]int
+}
+
+// add counts a review with the given rating in (sign 1) or out (sign -1).
+func (d *ratingDelta) add(rating int, sign int) {
+	if rating < 1 || rating > 5 {
+		return
+	}
+	d.count += sign
+	d.sum += sign * rating
+	d.stars[rating-1] += sign
+}
+
+// applyRatingDelta adjusts the master's counters inside the caller's
+// transaction. Increments keep concurrent review writes consistent without
+// recounting, the row lock serializes them per master.
+func applyRatingDelta(ctx context.Context, tx pgx.Tx, masterId int64, d ratingDelta) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO master_ratings AS mr (master_id, review_count, rating_sum, stars_1, stars_2, stars_3, stars_4, stars_5)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (master_id) DO UPDATE SET
+			review_count = mr.review_count + EXCLUDED.review_count,
+			rating_sum = mr.rating_sum + EXCLUDED.rating_sum,
+			stars_1 = mr.stars_1 + EXCLUDED.stars_1,
+			stars_2 = mr.stars_2 + EXCLUDED.stars_2,
+			stars_3 = mr.stars_3 + EXCLUDED.stars_3,
+			stars_4 = mr.stars_4 + EXCLUDED.stars_4,
+			stars_5 = mr.stars_5 + EXCLUDED.stars_5,
+			updated_at = NOW()
+	`, masterId, d.count, d.sum, d.stars[0], d.stars[1], d.stars[2], d.stars[3], d.stars[4])
+	return err
+}
+
+// RecomputeRatings rebuilds master_ratings from visible reviews and
+// returns the number of masters with reviews. Review writes wait until it
+// finishes, so no delta gets lost in between.
+func (r *reviewsRepo) RecomputeRatings(ctx context.Context) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `LOCK TABLE reviews IN SHARE MODE`); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM master_ratings`); err != nil {
+		return 0, err
+	}
+	cmd, err := tx.Exec(ctx, `
+		INSERT INTO master_ratings (master_id, review_count, rating_sum, stars_1, stars_2, stars_3, stars_4, stars_5)
+		SELECT master_id, COUNT(*), SUM(rating),
+			COUNT(*) FILTER (WHERE rating = 1),
+			COUNT(*) FILTER (WHERE rating = 2),
+			COUNT(*) FILTER (WHERE rating = 3),
+			COUNT(*) FILTER (WHERE rating = 4),
+			COUNT(*) FILTER (WHERE rating = 5)
+		FROM reviews
+		WHERE moderation = 'visible'
+		GROUP BY master_id
+	`)
+	if err != nil {
+		return 0, err
+	}
+
+	return cmd.RowsAffected(), tx.Commit(ctx)
+}
+
+// RecentRating returns how many reviews the master got since the given
+// time and the sum of their ratings. The window slides, so unlike the
+// totals it is not denormalized.
+func (r *reviewsRepo) RecentRating(ctx context.Context, masterId int64, since time.Time) (count int, sum int, err error) {
+	err = r.db.QueryRow(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(rating), 0)
+		FROM reviews
+		WHERE master_id = $1 AND created_at >= $2 AND moderation = 'visible'
+	`, masterId, since).Scan(&count, &sum)
+	return count, sum, err
+}
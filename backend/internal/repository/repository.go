@@ -15,6 +15,60 @@ type Repository struct {
 	Schedules
 	Reviews
 	VerificationCode
+	CancellationPolicies
+	ClientRatings
+	CalendarFeeds
+	TimeBlocks
+	SlotHolds
+	Idempotency
+	Suggestions
+}
+
+// Suggestions caches search bar suggestions by prefix.
+type Suggestions interface {
+	Get(ctx context.Context, prefix string, limit int) ([]models.Suggestion, error)
+	Set(ctx context.Context, prefix string, limit int, s []models.Suggestion, ttl time.Duration) error
+}
+
+type Idempotency interface {
+	Reserve(ctx context.Context, userId int64, key string, rec *models.IdempotencyRecord, ttl time.Duration) (bool, error)
+	Get(ctx context.Context, userId int64, key string) (*models.IdempotencyRecord, error)
+	Save(ctx context.Context, userId int64, key string, rec *models.IdempotencyRecord, ttl time.Duration) error
+	Delete(ctx context.Context, userId int64, key string) error
+}
+
+type SlotHolds interface {
+	Hold(ctx context.Context, h *models.SlotHold, ttl time.Duration) (bool, error)
+	Get(ctx context.Context, masterId int64, at time.Time) (*models.SlotHold, error)
+	GetByUser(ctx context.Context, userId int64) (*models.SlotHold, error)
+	Release(ctx context.Context, masterId int64, at time.Time, userId int64) error
+	GetHeldSlots(ctx context.Context, masterId int64, date time.Time) ([]string, error)
+}
+
+type TimeBlocks interface {
+	Create(ctx context.Context, b *models.TimeBlock) (int64, error)
+	GetById(ctx context.Context, id int64) (*models.TimeBlock, error)
+	Delete(ctx context.Context, id int64) error
+	ReplaceImported(ctx context.Context, masterId int64, blocks []models.TimeBlock) error
+	GetByRange(ctx context.Context, masterId int64, from, to time.Time) ([]models.TimeBlock, error)
+	GetImport(ctx context.Context, masterId int64) (*models.CalendarImport, error)
+	SetImportURL(ctx context.Context, masterId int64, url string) error
+}
+
+type CalendarFeeds interface {
+	GetToken(ctx context.Context, userId int64) (string, error)
+	SetToken(ctx context.Context, userId int64, token string) error
+	GetUserIdByToken(ctx context.Context, token string) (int64, error)
+}
+
+type CancellationPolicies interface {
+	Get(ctx context.Context, masterId int64) (*models.CancellationPolicy, error)
+	Upsert(ctx context.Context, p *models.CancellationPolicy) error
+}
+
+type ClientRatings interface {
+	Upsert(ctx context.Context, r *models.ClientRating) error
+	GetByClient(ctx context.Context, clientId int64) ([]models.ClientRating, error)
 }
 
 type VerificationCode interface {
@@ -26,10 +80,25 @@ type VerificationCode interface {
 type Reviews interface {
 	Create(ctx context.Context, r *models.Review) error
 	GetById(ctx context.Context, id int64) (*models.Review, error)
-	GetByMasterId(ctx context.Context, masterId int64) ([]models.Review, error)
+	GetByMasterId(ctx context.Context, f models.ReviewFilter, after *models.ReviewCursor) ([]models.Review, error)
 	Update(ctx context.Context, r *models.Review) error
+	GetRevisions(ctx context.Context, reviewId int64) ([]models.ReviewRevision, error)
 	Delete(ctx context.Context, id int64) error
-	AverageRatingOfMaster(ctx context.Context, id int64) (float64, error)
+	RecentRating(ctx context.Context, masterId int64, since time.Time) (count int, sum int, err error)
+	RecomputeRatings(ctx context.Context) (int64, error)
+	Report(ctx context.Context, rep *models.ReviewReport, hideThreshold int) (bool, error)
+	ModerationQueue(ctx context.Context, status string) ([]models.ModerationCase, error)
+	Resolve(ctx context.Context, reviewId int64, decision string, moderatorId int64) error
+	Vote(ctx context.Context, v *models.ReviewVote) error
+	CountByFingerprint(ctx context.Context, fingerprint string, excludeId int64) (int, error)
+	DeleteVote(ctx context.Context, reviewId, userId int64) error
+	CreateReply(ctx context.Context, reply *models.ReviewReply) error
+	UpdateReply(ctx context.Context, reply *models.ReviewReply) error
+	DeleteReply(ctx context.Context, reviewId int64) error
+	AddPhoto(ctx context.Context, p *models.ReviewPhoto, max int) error
+	GetPhoto(ctx context.Context, reviewId, photoId int64) (*models.ReviewPhoto, error)
+	GetPhotos(ctx context.Context, reviewIds []int64) (map[int64][]models.ReviewPhoto, error)
+	DeletePhoto(ctx context.Context, reviewId, photoId int64) error
 }
 
 type Schedules interface {
@@ -39,6 +108,10 @@ type Schedules interface {
 	DeleteWorkingSlotsByDate(ctx context.Context, userId int64, date time.Time) error
 	GetDaysOff(ctx context.Context, userId int64) ([]time.Time, error)
 	GetSlotsByDay(ctx context.Context, userId int64, date time.Time, dayOfWeek string) ([]time.Time, error)
+	SaveTemplate(ctx context.Context, userId int64, t *models.WeeklyTemplate) error
+	ReplaceWeek(ctx context.Context, userId int64, t *models.WeeklyTemplate, dates []models.DateSlots) error
+	GetTemplates(ctx context.Context, userId int64) ([]models.WeeklyTemplate, error)
+	DeleteTemplate(ctx context.Context, userId int64, effectiveFrom time.Time) error
 }
 
 type Users interface {
@@ -50,9 +123,10 @@ type Users interface {
 	GetByFullName(ctx context.Context, fn string) ([]models.User, error)
 	GetByUsername(ctx context.Context, un string) (*models.User, error)
 	GetByEmail(ctx context.Context, em string) (*models.User, error)
-	GetMastersByRating(ctx context.Context) ([]models.User, error)
+	FindMasters(ctx context.Context, f models.MasterFilter, now time.Time) ([]models.User, int, error)
 	GetMastersBySpecialization(ctx context.Context, s string) ([]models.User, error)
-	SearchUsers(ctx context.Context, query string) ([]models.User, error)
+	SearchUsers(ctx context.Context, text string, limit, offset int) ([]models.User, error)
+	Suggest(ctx context.Context, prefix string, limit int) ([]models.Suggestion, error)
 }
 
 type Appointments interface {
@@ -63,14 +137,33 @@ type Appointments interface {
 	GetByMasterId(ctx context.Context, id int64) ([]models.Appointment, error)
 	GetByDate(ctx context.Context, id int64, date time.Time) ([]models.Appointment, error)
 	GetByStatus(ctx context.Context, status string) ([]models.Appointment, error)
+	UpdateStatus(ctx context.Context, id int64, status string) error
+	Cancel(ctx context.Context, id int64, status string, canceledBy int64) error
+	GetClientStats(ctx context.Context, clientId int64) (*models.ClientStats, error)
+	HasBooked(ctx context.Context, clientId, masterId int64) (bool, error)
+	CheckAvailability(ctx context.Context, masterID int64, scheduledAt time.Time) error
+	CreateSeries(ctx context.Context, series *models.AppointmentSeries, apts []models.Appointment) ([]int64, error)
+	GetSeriesById(ctx context.Context, id int64) (*models.AppointmentSeries, error)
+	GetBySeriesId(ctx context.Context, seriesId int64) ([]models.Appointment, error)
+	GetByUserIdSince(ctx context.Context, id int64, since time.Time) ([]models.Appointment, error)
+	GetByMasterIdSince(ctx context.Context, id int64, since time.Time) ([]models.Appointment, error)
+	History(ctx context.Context, f models.HistoryFilter, after *models.HistoryCursor) ([]models.Appointment, error)
+	GetAwaitingReview(ctx context.Context, userId int64) ([]models.Appointment, error)
 }
 
 func New(db *pgxpool.Pool, redis *redis.Client) *Repository {
 	return &Repository{
-		Users:            newPostgresUsersRepository(db),
-		Appointments:     newPostgresAppointmentsRepository(db),
-		Schedules:        newPostgresSchedulesRepository(db),
-		Reviews:          newPostgresReviewsRepo(db),
-		VerificationCode: newRedisVerificationCodeRepo(redis),
+		Users:                newPostgresUsersRepository(db),
+		Appointments:         newPostgresAppointmentsRepository(db),
+		Schedules:            newPostgresSchedulesRepository(db),
+		Reviews:              newPostgresReviewsRepo(db),
+		VerificationCode:     newRedisVerificationCodeRepo(redis),
+		CancellationPolicies: newPostgresCancellationPoliciesRepository(db),
+		ClientRatings:        newPostgresClientRatingsRepository(db),
+		CalendarFeeds:        newPostgresCalendarFeedsRepository(db),
+		TimeBlocks:           newPostgresTimeBlocksRepository(db),
+		SlotHolds:            newRedisSlotHoldsRepo(redis),
+		Idempotency:          newRedisIdempotencyRepo(redis),
+		Suggestions:          newRedisSuggestionsRepo(redis),
 	}
 }
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strawberry/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Report files a report and hides the review once hideThreshold distinct
+// users have open reports on it. It tells whether this report hid the review.
+func (r *reviewsRepo) Report(ctx context.Context, rep *models.ReviewReport, hideThreshold int) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Concurrent reports of the review queue up here, so each one counts
+	// the reports committed before it and the threshold is not missed.
+	var id int64
+	err = tx.QueryRow(ctx, `SELECT id FROM reviews WHERE id = $1 FOR UPDATE`, rep.ReviewId).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, ErrNotFound
+		}
+		return false, err
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO review_reports (review_id, reporter_id, reason, comment)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, status, created_at
+	`, rep.ReviewId, rep.ReporterId, rep.Reason, rep.Comment).Scan(&rep.Id, &rep.Status, &rep.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505":
+				return false, ErrConflict
+			case "23503":
+				return false, ErrNotFound
+			}
+		}
+		return false, err
+	}
+
+	var open int
+	err = tx.QueryRow(ctx, `
+		SELECT COUNT(DISTINCT reporter_id) FROM review_reports
+		WHERE review_id = $1 AND status = 'open'
+	`, rep.ReviewId).Scan(&open)
+	if err != nil {
+		return false, err
+	}
+
+	hidden := false
+	if open >= hideThreshold {
+		hidden, err = setModeration(ctx, tx, rep.ReviewId, models.ReviewVisible, models.ReviewHidden)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return hidden, tx.Commit(ctx)
+}
+
+// ModerationQueue returns reported reviews with their reports in the given
+// status, oldest report first. Open cases also include reviews hidden
+// without reports, e.g. by automatic screening.
+func (r *reviewsRepo) ModerationQueue(ctx context.Context, status string) ([]models.ModerationCase, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT r.id, r.user_id, r.master_id, r.appointment_id, r.rating, r.comment, r.moderation, r.created_at, r.updated_at, r.screening,
+			rp.id, rp.reporter_id, rp.reason, rp.comment, rp.status, rp.created_at, rp.resolved_at, rp.resolved_by
+		FROM reviews r
+		LEFT JOIN review_reports rp ON rp.review_id = r.id AND rp.status = $1
+		WHERE rp.id IS NOT NULL OR ($1 = 'open' AND r.moderation = 'hidden')
+		ORDER BY r.id, rp.created_at
+	`, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cases []models.ModerationCase
+	for rows.Next() {
+		var (
+			rev        models.Review
+			reportId   *int64
+			reporterId *int64
+			reason     *string
+			comment    *string
+			repStatus  *string
+			createdAt  *time.Time
+			resolvedAt *time.Time
+			resolvedBy *int64
+		)
+		if err := rows.Scan(&rev.Id, &rev.UserId, &rev.MasterId, &rev.AppointmentId, &rev.Rating, &rev.Comment, &rev.Moderation, &rev.CreatedAt, &rev.UpdatedAt, &rev.Screening,
+			&reportId, &reporterId, &reason, &comment, &repStatus, &createdAt, &resolvedAt, &resolvedBy); err != nil {
+			return nil, err
+		}
+
+		if n := len(cases); n == 0 || cases[n-1].Review.Id != rev.Id {
+			cases = append(cases, models.ModerationCase{Review: rev, Reports: []models.ReviewReport{}})
+		}
+		if reportId != nil {
+			c := &cases[len(cases)-1]
+			c.Reports = append(c.Reports, models.ReviewReport{
+				Id:         *reportId,
+				ReviewId:   rev.Id,
+				ReporterId: *reporterId,
+				Reason:     *reason,
+				Comment:    *comment,
+				Status:     *repStatus,
+				CreatedAt:  *createdAt,
+				ResolvedAt: resolvedAt,
+				ResolvedBy: resolvedBy,
+			})
+		}
+	}
+	return cases, rows.Err()
+}
+
+// Resolve closes the open reports of a review with the moderator's decision.
+// Dismissing makes a hidden review visible again, removing takes it out for good.
+func (r *reviewsRepo) Resolve(ctx context.Context, reviewId int64, decision string, moderatorId int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var moderation string
+	err = tx.QueryRow(ctx, `SELECT moderation FROM reviews WHERE id = $1 FOR UPDATE`, reviewId).Scan(&moderation)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	cmd, err := tx.Exec(ctx, `
+		UPDATE review_reports
+		SET status = $2, resolved_at = NOW(), resolved_by = $3
+		WHERE review_id = $1 AND status = 'open'
+	`, reviewId, decision, moderatorId)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 && moderation != models.ReviewHidden {
+		return ErrNotFound
+	}
+
+	switch decision {
+	case models.ReportDismissed:
+		_, err = setModeration(ctx, tx, reviewId, models.ReviewHidden, models.ReviewVisible)
+	case models.ReportRemoved:
+		_, err = setModeration(ctx, tx, reviewId, moderation, models.ReviewRemoved)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// setModeration moves a review from one moderation state to another and
+// keeps the master's rating counters in line with its visibility. It
+// reports false if the review was not in the expected state.
+func setModeration(ctx context.Context, tx pgx.Tx, reviewId int64, from, to string) (bool, error) {
+	var (
+		masterId int64
+		rating   int
+	)
+	err := tx.QueryRow(ctx, `
+		UPDATE reviews SET moderation = $3
+		WHERE id = $1 AND moderation = $2
+		RETURNING master_id, rating
+	`, reviewId, from, to).Scan(&masterId, &rating)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var delta ratingDelta
+	if from == models.ReviewVisible && to != models.ReviewVisible {
+		delta.add(rating, -1)
+	}
+	if from != models.ReviewVisible && to == models.ReviewVisible {
+		delta.add(rating, 1)
+	}
+	if delta.count != 0 {
+		if err := applyRatingDelta(ctx, tx, masterId, delta); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
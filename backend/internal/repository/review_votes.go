@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strawberry/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Vote records or changes the user's vote and keeps the review's counters
+// in step with it.
+func (r *reviewsRepo) Vote(ctx context.Context, v *models.ReviewVote) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var prev *bool
+	err = tx.QueryRow(ctx, `
+		SELECT v.helpful
+		FROM reviews r
+		LEFT JOIN review_votes v ON v.review_id = r.id AND v.user_id = $2
+		WHERE r.id = $1
+		FOR UPDATE OF r
+	`, v.ReviewId, v.UserId).Scan(&prev)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+	if prev != nil && *prev == v.Helpful {
+		return nil
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO review_votes (review_id, user_id, helpful)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (review_id, user_id) DO UPDATE SET helpful = EXCLUDED.helpful, updated_at = NOW()
+	`, v.ReviewId, v.UserId, v.Helpful)
+	if err != nil {
+		return err
+	}
+
+	var helpful, unhelpful int
+	if v.Helpful {
+		helpful++
+	} else {
+		unhelpful++
+	}
+	if prev != nil {
+		if *prev {
+			helpful--
+		} else {
+			unhelpful--
+		}
+	}
+	if err := applyVoteDelta(ctx, tx, v.ReviewId, helpful, unhelpful); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// DeleteVote withdraws the user's vote.
+func (r *reviewsRepo) DeleteVote(ctx context.Context, reviewId, userId int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var helpful bool
+	err = tx.QueryRow(ctx, `
+		DELETE FROM review_votes WHERE review_id = $1 AND user_id = $2
+		RETURNING helpful
+	`, reviewId, userId).Scan(&helpful)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	dHelpful, dUnhelpful := 0, -1
+	if helpful {
+		dHelpful, dUnhelpful = -1, 0
+	}
+	if err := applyVoteDelta(ctx, tx, reviewId, dHelpful, dUnhelpful); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func applyVoteDelta(ctx context.Context, tx pgx.Tx, reviewId int64, helpful, unhelpful int) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE reviews
+		SET helpful_count = helpful_count + $2, unhelpful_count = unhelpful_count + $3
+		WHERE id = $1
+	`, reviewId, helpful, unhelpful)
+	return err
+}
@@ -2,10 +2,11 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"fmt"
 	"strawberry/internal/models"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -20,19 +21,32 @@ var (
 	ErrNotFound         = errors.New("resource not found")
 	ErrConflict         = errors.New("resource already exists")
 	ErrInvalidReference = errors.New("invalid foreign key reference")
+	ErrPhotoLimit       = errors.New("review photo limit reached")
 )
 
 func newPostgresReviewsRepo(db *pgxpool.Pool) Reviews {
 	return &reviewsRepo{db: db}
 }
 
+// Create inserts the review and counts it into the master's rating in one transaction.
 func (r *reviewsRepo) Create(ctx context.Context, rev *models.Review) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
-		INSERT INTO reviews (user_id, master_id, rating, comment, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		INSERT INTO reviews (user_id, master_id, appointment_id, rating, comment, moderation,
+			comment_fingerprint, screening, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, NOW(), NOW())
 		RETURNING id, created_at, updated_at
 	`
-	err := r.db.QueryRow(ctx, query, rev.UserId, rev.MasterId, rev.Rating, rev.Comment).
+	if rev.Moderation == "" {
+		rev.Moderation = models.ReviewVisible
+	}
+	err = tx.QueryRow(ctx, query, rev.UserId, rev.MasterId, rev.AppointmentId, rev.Rating, rev.Comment, rev.Moderation,
+		models.CommentFingerprint(rev.Comment), screeningFindings(rev.Screening)).
 		Scan(&rev.Id, &rev.CreatedAt, &rev.UpdatedAt)
 
 	if err != nil {
@@ -48,19 +62,28 @@ func (r *reviewsRepo) Create(ctx context.Context, rev *models.Review) error {
 		return err
 	}
 
-	return nil
+	if rev.Moderation == models.ReviewVisible {
+		var delta ratingDelta
+		delta.add(rev.Rating, 1)
+		if err := applyRatingDelta(ctx, tx, rev.MasterId, delta); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
 }
 
 func (r *reviewsRepo) GetById(ctx context.Context, id int64) (*models.Review, error) {
 	query := `
-		SELECT id, user_id, master_id, rating, comment, created_at, updated_at
+		SELECT id, user_id, master_id, appointment_id, rating, comment, moderation, created_at, updated_at, edited_at,
+			helpful_count, unhelpful_count, screening
 		FROM reviews
 		WHERE id = $1
 	`
 	var rev models.Review
 	err := r.db.QueryRow(ctx, query, id).Scan(
-		&rev.Id, &rev.UserId, &rev.MasterId, &rev.Rating, &rev.Comment,
-		&rev.CreatedAt, &rev.UpdatedAt,
+		&rev.Id, &rev.UserId, &rev.MasterId, &rev.AppointmentId, &rev.Rating, &rev.Comment, &rev.Moderation,
+		&rev.CreatedAt, &rev.UpdatedAt, &rev.EditedAt, &rev.HelpfulCount, &rev.UnhelpfulCount, &rev.Screening,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -68,17 +91,66 @@ func (r *reviewsRepo) GetById(ctx context.Context, id int64) (*models.Review, er
 		}
 		return nil, err
 	}
+	rev.Edited = rev.EditedAt != nil
 	return &rev, nil
 }
 
-func (r *reviewsRepo) GetByMasterId(ctx context.Context, masterId int64) ([]models.Review, error) {
+// GetByMasterId returns a page of the master's visible reviews. The page
+// continues after the given cursor, which must come from the same sort order.
+func (r *reviewsRepo) GetByMasterId(ctx context.Context, f models.ReviewFilter, after *models.ReviewCursor) ([]models.Review, error) {
+	args := []any{f.MasterID}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	where := []string{"r.master_id = $1", "r.moderation = 'visible'"}
+	if len(f.Ratings) > 0 {
+		where = append(where, "r.rating = ANY("+arg(f.Ratings)+")")
+	}
+	if f.WithPhotos {
+		where = append(where, "EXISTS (SELECT 1 FROM review_photos p WHERE p.review_id = r.id)")
+	}
+	if f.WithComment {
+		where = append(where, "COALESCE(btrim(r.comment), '') <> ''")
+	}
+
+	order := "r.created_at DESC, r.id DESC"
+	switch f.Sort {
+	case models.ReviewSortHighest:
+		order = "r.rating DESC, " + order
+		if after != nil {
+			where = append(where, fmt.Sprintf("(r.rating, r.created_at, r.id) < (%s, %s, %s)",
+				arg(after.Rating), arg(after.CreatedAt), arg(after.ID)))
+		}
+	case models.ReviewSortLowest:
+		order = "r.rating ASC, " + order
+		if after != nil {
+			rating := arg(after.Rating)
+			where = append(where, fmt.Sprintf("(r.rating > %s OR (r.rating = %s AND (r.created_at, r.id) < (%s, %s)))",
+				rating, rating, arg(after.CreatedAt), arg(after.ID)))
+		}
+	case models.ReviewSortMostHelpful:
+		order = "r.helpful_count DESC, " + order
+		if after != nil {
+			where = append(where, fmt.Sprintf("(r.helpful_count, r.created_at, r.id) < (%s, %s, %s)",
+				arg(after.Helpful), arg(after.CreatedAt), arg(after.ID)))
+		}
+	default:
+		if after != nil {
+			where = append(where, fmt.Sprintf("(r.created_at, r.id) < (%s, %s)", arg(after.CreatedAt), arg(after.ID)))
+		}
+	}
+
 	query := `
-		SELECT id, user_id, master_id, rating, comment, created_at, updated_at
-		FROM reviews
-		WHERE master_id = $1
-		ORDER BY created_at DESC
-	`
-	rows, err := r.db.Query(ctx, query, masterId)
+		SELECT r.id, r.user_id, r.master_id, r.appointment_id, r.rating, COALESCE(r.comment, ''), r.created_at, r.updated_at, r.edited_at,
+			r.helpful_count, r.unhelpful_count, rr.text, rr.created_at, rr.updated_at
+		FROM reviews r
+		LEFT JOIN review_replies rr ON rr.review_id = r.id
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY ` + order + `
+		LIMIT ` + arg(f.Limit)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get reviews for master: %w", err)
 	}
@@ -86,23 +158,187 @@ func (r *reviewsRepo) GetByMasterId(ctx context.Context, masterId int64) ([]mode
 
 	var reviews []models.Review
 	for rows.Next() {
-		var rev models.Review
-		if err := rows.Scan(&rev.Id, &rev.UserId, &rev.MasterId, &rev.Rating, &rev.Comment, &rev.CreatedAt, &rev.UpdatedAt); err != nil {
+		var (
+			rev                        models.Review
+			replyText                  *string
+			replyCreated, replyUpdated *time.Time
+		)
+		if err := rows.Scan(&rev.Id, &rev.UserId, &rev.MasterId, &rev.AppointmentId, &rev.Rating, &rev.Comment, &rev.CreatedAt, &rev.UpdatedAt, &rev.EditedAt,
+			&rev.HelpfulCount, &rev.UnhelpfulCount, &replyText, &replyCreated, &replyUpdated); err != nil {
 			return nil, err
 		}
+		rev.Edited = rev.EditedAt != nil
+		if replyText != nil {
+			rev.Reply = &models.ReviewReply{
+				ReviewId:  rev.Id,
+				MasterId:  rev.MasterId,
+				Text:      *replyText,
+				CreatedAt: *replyCreated,
+				UpdatedAt: *replyUpdated,
+			}
+		}
 		reviews = append(reviews, rev)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(reviews))
+	for i := range reviews {
+		ids[i] = reviews[i].Id
+	}
+	photos, err := r.GetPhotos(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review photos: %w", err)
+	}
+	for i := range reviews {
+		reviews[i].Photos = photos[reviews[i].Id]
+	}
 
 	return reviews, nil
 }
 
+// Update stores the edited review and keeps the replaced version as a
+// revision. A visible review marked hidden by screening is taken out of the
+// rating; otherwise the moderation state is kept as is.
 func (r *reviewsRepo) Update(ctx context.Context, rev *models.Review) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		masterId   int64
+		oldRating  int
+		oldComment string
+		writtenAt  time.Time
+		moderation string
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT master_id, rating, COALESCE(comment, ''), COALESCE(edited_at, created_at), moderation
+		FROM reviews WHERE id = $1 FOR UPDATE
+	`, rev.Id).Scan(&masterId, &oldRating, &oldComment, &writtenAt, &moderation)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	newModeration := moderation
+	if rev.Moderation == models.ReviewHidden && moderation == models.ReviewVisible {
+		newModeration = models.ReviewHidden
+	}
+
+	edited := oldRating != rev.Rating || oldComment != rev.Comment
+	if edited {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO review_revisions (review_id, rating, comment, written_at)
+			VALUES ($1, $2, $3, $4)
+		`, rev.Id, oldRating, oldComment, writtenAt)
+		if err != nil {
+			return err
+		}
+	}
+
 	query := `
 		UPDATE reviews
-		SET rating = $1, comment = $2, updated_at = NOW()
-		WHERE id = $3
+		SET rating = $1, comment = $2, comment_fingerprint = NULLIF($3, ''), screening = $4, moderation = $5, updated_at = NOW(),
+			edited_at = CASE WHEN $6 THEN NOW() ELSE edited_at END
+		WHERE id = $7
+		RETURNING created_at, updated_at, edited_at
 	`
-	cmd, err := r.db.Exec(ctx, query, rev.Rating, rev.Comment, rev.Id)
+	err = tx.QueryRow(ctx, query, rev.Rating, rev.Comment, models.CommentFingerprint(rev.Comment), screeningFindings(rev.Screening),
+		newModeration, edited, rev.Id).Scan(&rev.CreatedAt, &rev.UpdatedAt, &rev.EditedAt)
+	if err != nil {
+		return err
+	}
+	rev.Moderation = newModeration
+	rev.Edited = rev.EditedAt != nil
+
+	if moderation == models.ReviewVisible {
+		var delta ratingDelta
+		switch {
+		case newModeration != models.ReviewVisible:
+			delta.add(oldRating, -1)
+		case oldRating != rev.Rating:
+			delta.add(oldRating, -1)
+			delta.add(rev.Rating, 1)
+		}
+		if delta.count != 0 || delta.sum != 0 {
+			if err := applyRatingDelta(ctx, tx, masterId, delta); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *reviewsRepo) Delete(ctx context.Context, id int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		masterId   int64
+		rating     int
+		moderation string
+	)
+	err = tx.QueryRow(ctx, `DELETE FROM reviews WHERE id = $1 RETURNING master_id, rating, moderation`, id).
+		Scan(&masterId, &rating, &moderation)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	if moderation == models.ReviewVisible {
+		var delta ratingDelta
+		delta.add(rating, -1)
+		if err := applyRatingDelta(ctx, tx, masterId, delta); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *reviewsRepo) CreateReply(ctx context.Context, reply *models.ReviewReply) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO review_replies (review_id, master_id, text)
+		VALUES ($1, $2, $3)
+		RETURNING created_at, updated_at
+	`, reply.ReviewId, reply.MasterId, reply.Text).Scan(&reply.CreatedAt, &reply.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrConflict
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *reviewsRepo) UpdateReply(ctx context.Context, reply *models.ReviewReply) error {
+	err := r.db.QueryRow(ctx, `
+		UPDATE review_replies
+		SET text = $1, updated_at = NOW()
+		WHERE review_id = $2
+		RETURNING master_id, created_at, updated_at
+	`, reply.Text, reply.ReviewId).Scan(&reply.MasterId, &reply.CreatedAt, &reply.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (r *reviewsRepo) DeleteReply(ctx context.Context, reviewId int64) error {
+	cmd, err := r.db.Exec(ctx, `DELETE FROM review_replies WHERE review_id = $1`, reviewId)
 	if err != nil {
 		return err
 	}
@@ -112,9 +348,86 @@ func (r *reviewsRepo) Update(ctx context.Context, rev *models.Review) error {
 	return nil
 }
 
-func (r *reviewsRepo) Delete(ctx context.Context, id int64) error {
-	query := `DELETE FROM reviews WHERE id = $1`
-	cmd, err := r.db.Exec(ctx, query, id)
+// AddPhoto stores photo metadata unless the review already has max photos.
+func (r *reviewsRepo) AddPhoto(ctx context.Context, p *models.ReviewPhoto, max int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Concurrent uploads to the review queue up here so that each one
+	// counts the photos committed before it.
+	var id int64
+	err = tx.QueryRow(ctx, `SELECT id FROM reviews WHERE id = $1 FOR UPDATE`, p.ReviewId).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO review_photos (review_id, content_type, size)
+		SELECT $1, $2, $3
+		WHERE (SELECT COUNT(*) FROM review_photos WHERE review_id = $1) < $4
+		RETURNING id, created_at
+	`, p.ReviewId, p.ContentType, p.Size, max).Scan(&p.Id, &p.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrPhotoLimit
+		}
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *reviewsRepo) GetPhoto(ctx context.Context, reviewId, photoId int64) (*models.ReviewPhoto, error) {
+	var p models.ReviewPhoto
+	err := r.db.QueryRow(ctx, `
+		SELECT id, review_id, content_type, size, created_at
+		FROM review_photos
+		WHERE id = $1 AND review_id = $2
+	`, photoId, reviewId).Scan(&p.Id, &p.ReviewId, &p.ContentType, &p.Size, &p.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetPhotos returns photos of the given reviews grouped by review id.
+func (r *reviewsRepo) GetPhotos(ctx context.Context, reviewIds []int64) (map[int64][]models.ReviewPhoto, error) {
+	photos := make(map[int64][]models.ReviewPhoto)
+	if len(reviewIds) == 0 {
+		return photos, nil
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, review_id, content_type, size, created_at
+		FROM review_photos
+		WHERE review_id = ANY($1)
+		ORDER BY id
+	`, reviewIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p models.ReviewPhoto
+		if err := rows.Scan(&p.Id, &p.ReviewId, &p.ContentType, &p.Size, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		photos[p.ReviewId] = append(photos[p.ReviewId], p)
+	}
+	return photos, rows.Err()
+}
+
+func (r *reviewsRepo) DeletePhoto(ctx context.Context, reviewId, photoId int64) error {
+	cmd, err := r.db.Exec(ctx, `DELETE FROM review_photos WHERE id = $1 AND review_id = $2`, photoId, reviewId)
 	if err != nil {
 		return err
 	}
@@ -124,18 +437,43 @@ func (r *reviewsRepo) Delete(ctx context.Context, id int64) error {
 	return nil
 }
 
-func (r *reviewsRepo) AverageRatingOfMaster(ctx context.Context, masterId int64) (float64, error) {
-	var avg sql.NullFloat64
-
+// CountByFingerprint counts other reviews whose comment has the given fingerprint.
+func (r *reviewsRepo) CountByFingerprint(ctx context.Context, fingerprint string, excludeId int64) (int, error) {
+	var n int
 	err := r.db.QueryRow(ctx, `
-        SELECT AVG(rating) FROM reviews WHERE master_id = $1
-    `, masterId).Scan(&avg)
+		SELECT COUNT(*) FROM reviews WHERE comment_fingerprint = $1 AND id <> $2
+	`, fingerprint, excludeId).Scan(&n)
+	return n, err
+}
+
+// screeningFindings keeps an empty screening stored as [] rather than null.
+func screeningFindings(f []models.ScreeningFinding) []models.ScreeningFinding {
+	if f == nil {
+		return []models.ScreeningFinding{}
+	}
+	return f
+}
+
+// GetRevisions returns the replaced versions of a review, oldest first.
+func (r *reviewsRepo) GetRevisions(ctx context.Context, reviewId int64) ([]models.ReviewRevision, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, review_id, rating, comment, written_at, replaced_at
+		FROM review_revisions
+		WHERE review_id = $1
+		ORDER BY id
+	`, reviewId)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
+	defer rows.Close()
 
-	if avg.Valid {
-		return avg.Float64, nil
+	revisions := []models.ReviewRevision{}
+	for rows.Next() {
+		var rv models.ReviewRevision
+		if err := rows.Scan(&rv.Id, &rv.ReviewId, &rv.Rating, &rv.Comment, &rv.WrittenAt, &rv.ReplacedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rv)
 	}
-	return 0, nil
+	return revisions, rows.Err()
 }
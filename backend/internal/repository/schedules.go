@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"strawberry/internal/models"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -30,6 +31,7 @@ func (r *postgresSchedulesRepository) SetDayOff(ctx context.Context, userID int6
 	return err
 }
 
+// SetWorkingSlotsByWeekDay edits the template in effect today, creating the initial one if there is none.
 func (r *postgresSchedulesRepository) SetWorkingSlotsByWeekDay(ctx context.Context, userID int64, dayOfWeek string, slots []string) error {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
@@ -37,18 +39,35 @@ func (r *postgresSchedulesRepository) SetWorkingSlotsByWeekDay(ctx context.Conte
 	}
 	defer tx.Rollback(ctx)
 
+	var effectiveFrom time.Time
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(MAX(effective_from), DATE '1970-01-01')
+		FROM schedule_templates
+		WHERE user_id = $1 AND effective_from <= CURRENT_DATE
+	`, userID).Scan(&effectiveFrom)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO schedule_templates (user_id, effective_from) VALUES ($1, $2) ON CONFLICT DO NOTHING
+	`, userID, effectiveFrom)
+	if err != nil {
+		return err
+	}
+
 	_, err = tx.Exec(ctx, `
-		DELETE FROM schedule_slots WHERE user_id = $1 AND day_of_week = LOWER($2)
-	`, userID, dayOfWeek)
+		DELETE FROM schedule_slots WHERE user_id = $1 AND effective_from = $2 AND day_of_week = LOWER($3)
+	`, userID, effectiveFrom, dayOfWeek)
 	if err != nil {
 		return err
 	}
 
 	for _, slot := range slots {
 		_, err := tx.Exec(ctx, `
-			INSERT INTO schedule_slots (user_id, day_of_week, slot)
-			VALUES ($1, LOWER($2), $3::time)
-		`, userID, dayOfWeek, slot)
+			INSERT INTO schedule_slots (user_id, effective_from, day_of_week, slot)
+			VALUES ($1, $2, LOWER($3), $4::time)
+		`, userID, effectiveFrom, dayOfWeek, slot)
 		if err != nil {
 			return err
 		}
@@ -57,16 +76,75 @@ func (r *postgresSchedulesRepository) SetWorkingSlotsByWeekDay(ctx context.Conte
 	return tx.Commit(ctx)
 }
 
-func (r *postgresSchedulesRepository) SetWorkingSlotsByDate(ctx context.Context, userId int64, date time.Time, slots []string) error {
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+// SaveTemplate creates or replaces the whole-week template starting at t.EffectiveFrom.
+func (r *postgresSchedulesRepository) SaveTemplate(ctx context.Context, userId int64, t *models.WeeklyTemplate) error {
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback(ctx)
 
+	if err := saveTemplate(ctx, tx, userId, t); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// ReplaceWeek saves the template and date overrides in one transaction.
+// Overrides with no slots are removed, so the date falls back to the template.
+func (r *postgresSchedulesRepository) ReplaceWeek(ctx context.Context, userId int64, t *models.WeeklyTemplate, dates []models.DateSlots) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := saveTemplate(ctx, tx, userId, t); err != nil {
+		return err
+	}
+	for _, d := range dates {
+		if err := replaceDateSlots(ctx, tx, userId, d.Date, d.Slots); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func saveTemplate(ctx context.Context, tx pgx.Tx, userId int64, t *models.WeeklyTemplate) error {
+	err := tx.QueryRow(ctx, `
+		INSERT INTO schedule_templates (user_id, effective_from) VALUES ($1, $2)
+		ON CONFLICT (user_id, effective_from) DO UPDATE SET created_at = CURRENT_TIMESTAMP
+		RETURNING created_at
+	`, userId, t.EffectiveFrom).Scan(&t.CreatedAt)
+	if err != nil {
+		return err
+	}
+
 	_, err = tx.Exec(ctx, `
+		DELETE FROM schedule_slots WHERE user_id = $1 AND effective_from = $2
+	`, userId, t.EffectiveFrom)
+	if err != nil {
+		return err
+	}
+
+	for day, slots := range t.Days {
+		for _, slot := range slots {
+			_, err := tx.Exec(ctx, `
+				INSERT INTO schedule_slots (user_id, effective_from, day_of_week, slot)
+				VALUES ($1, $2, $3, $4::time)
+			`, userId, t.EffectiveFrom, day, slot)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func replaceDateSlots(ctx context.Context, tx pgx.Tx, userId int64, date string, slots []string) error {
+	_, err := tx.Exec(ctx, `
 		DELETE FROM date_slots WHERE user_id = $1 AND date = $2
-	`, userId, date.Format("2006-01-02"))
+	`, userId, date)
 	if err != nil {
 		return err
 	}
@@ -74,11 +152,79 @@ func (r *postgresSchedulesRepository) SetWorkingSlotsByDate(ctx context.Context,
 	for _, slot := range slots {
 		_, err := tx.Exec(ctx, `
 			INSERT INTO date_slots (user_id, date, slot) VALUES ($1, $2, $3)
-		`, userId, date.Format("2006-01-02"), slot)
+		`, userId, date, slot)
 		if err != nil {
 			return err
 		}
 	}
+	return nil
+}
+
+// GetTemplates returns all templates of the user ordered by effective date.
+func (r *postgresSchedulesRepository) GetTemplates(ctx context.Context, userId int64) ([]models.WeeklyTemplate, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT t.effective_from, t.created_at, s.day_of_week, s.slot
+		FROM schedule_templates t
+		LEFT JOIN schedule_slots s ON s.user_id = t.user_id AND s.effective_from = t.effective_from
+		WHERE t.user_id = $1
+		ORDER BY t.effective_from, s.slot;
+	`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []models.WeeklyTemplate
+	for rows.Next() {
+		var (
+			effectiveFrom time.Time
+			createdAt     time.Time
+			day           *string
+			slot          *time.Time
+		)
+		if err := rows.Scan(&effectiveFrom, &createdAt, &day, &slot); err != nil {
+			return nil, err
+		}
+
+		date := effectiveFrom.Format("2006-01-02")
+		if len(templates) == 0 || templates[len(templates)-1].EffectiveFrom != date {
+			templates = append(templates, models.WeeklyTemplate{
+				EffectiveFrom: date,
+				Days:          map[string][]string{},
+				CreatedAt:     createdAt,
+			})
+		}
+		if day != nil && slot != nil {
+			t := &templates[len(templates)-1]
+			t.Days[*day] = append(t.Days[*day], slot.Format("15:04"))
+		}
+	}
+	return templates, rows.Err()
+}
+
+func (r *postgresSchedulesRepository) DeleteTemplate(ctx context.Context, userId int64, effectiveFrom time.Time) error {
+	cmdTag, err := r.db.Exec(ctx, `
+		DELETE FROM schedule_templates WHERE user_id = $1 AND effective_from = $2
+	`, userId, effectiveFrom)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *postgresSchedulesRepository) SetWorkingSlotsByDate(ctx context.Context, userId int64, date time.Time, slots []string) error {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := replaceDateSlots(ctx, tx, userId, date.Format("2006-01-02"), slots); err != nil {
+		return err
+	}
 
 	return tx.Commit(ctx)
 }
@@ -146,10 +292,13 @@ func (r *postgresSchedulesRepository) GetSlotsByDay(ctx context.Context, userId
 	queryScheduleSlots :=
 		`SELECT slot 
         FROM schedule_slots 
-        WHERE user_id = $1 AND day_of_week = LOWER($2)
+        WHERE user_id = $1 AND day_of_week = LOWER($2) AND effective_from = (
+            SELECT MAX(effective_from) FROM schedule_templates
+            WHERE user_id = $1 AND effective_from <= $3
+        )
         ORDER BY slot;`
 
-	rows, err = r.db.Query(ctx, queryScheduleSlots, userId, dayOfWeek)
+	rows, err = r.db.Query(ctx, queryScheduleSlots, userId, dayOfWeek, date.Format("2006-01-02"))
 	if err != nil {
 		return nil, err
 	}
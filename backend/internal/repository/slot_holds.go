@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strawberry/internal/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+const holdTimeLayout = "2006-01-02T15:04"
+
+// holdScript sets the slot key unless another user holds it and indexes the
+// slot by date for schedule queries. Returns 0 if the slot is taken.
+var holdScript = redis.NewScript(`
+local cur = redis.call('GET', KEYS[1])
+if cur and cur ~= ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+redis.call('ZADD', KEYS[2], ARGV[3], ARGV[4])
+redis.call('PEXPIRE', KEYS[2], ARGV[2])
+redis.call('SET', KEYS[3], ARGV[5], 'PX', ARGV[2])
+return 1
+`)
+
+// releaseScript removes the hold only if it belongs to the given user.
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+redis.call('DEL', KEYS[1])
+redis.call('ZREM', KEYS[2], ARGV[2])
+if redis.call('GET', KEYS[3]) == ARGV[3] then
+	redis.call('DEL', KEYS[3])
+end
+return 1
+`)
+
+type SlotHoldsRepo struct {
+	redis *redis.Client
+}
+
+func newRedisSlotHoldsRepo(redis *redis.Client) SlotHolds {
+	return &SlotHoldsRepo{redis: redis}
+}
+
+func holdKey(masterId int64, at time.Time) string {
+	return fmt.Sprintf("hold:%d:%s", masterId, at.Format(holdTimeLayout))
+}
+
+func holdIndexKey(masterId int64, date time.Time) string {
+	return fmt.Sprintf("holds:%d:%s", masterId, date.Format("2006-01-02"))
+}
+
+func userHoldKey(userId int64) string {
+	return fmt.Sprintf("hold:user:%d", userId)
+}
+
+func userHoldValue(masterId int64, at time.Time) string {
+	return fmt.Sprintf("%d|%s", masterId, at.Format(holdTimeLayout))
+}
+
+// Hold reserves the slot for h.UserID. It returns false if another user holds it;
+// the user's own hold is prolonged.
+func (r *SlotHoldsRepo) Hold(ctx context.Context, h *models.SlotHold, ttl time.Duration) (bool, error) {
+	expiresAt := time.Now().Add(ttl)
+	res, err := holdScript.Run(r.redis,
+		[]string{holdKey(h.MasterID, h.Time), holdIndexKey(h.MasterID, h.Time), userHoldKey(h.UserID)},
+		h.UserID, ttl.Milliseconds(), expiresAt.Unix(), h.Time.Format("15:04"), userHoldValue(h.MasterID, h.Time),
+	).Int()
+	if err != nil {
+		return false, err
+	}
+	if res == 0 {
+		return false, nil
+	}
+	h.ExpiresAt = expiresAt
+	return true, nil
+}
+
+// Get returns the hold of the slot or ErrNotFound if it is free.
+func (r *SlotHoldsRepo) Get(ctx context.Context, masterId int64, at time.Time) (*models.SlotHold, error) {
+	key := holdKey(masterId, at)
+	val, err := r.redis.Get(key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	userId, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	ttl, err := r.redis.PTTL(key).Result()
+	if err != nil {
+		return nil, err
+	}
+	return &models.SlotHold{
+		MasterID:  masterId,
+		UserID:    userId,
+		Time:      at,
+		ExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+// GetByUser returns the slot currently held by the user or ErrNotFound.
+func (r *SlotHoldsRepo) GetByUser(ctx context.Context, userId int64) (*models.SlotHold, error) {
+	val, err := r.redis.Get(userHoldKey(userId)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	masterStr, atStr, ok := strings.Cut(val, "|")
+	if !ok {
+		return nil, ErrNotFound
+	}
+	masterId, err := strconv.ParseInt(masterStr, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	at, err := time.Parse(holdTimeLayout, atStr)
+	if err != nil {
+		return nil, err
+	}
+	return r.Get(ctx, masterId, at)
+}
+
+func (r *SlotHoldsRepo) Release(ctx context.Context, masterId int64, at time.Time, userId int64) error {
+	return releaseScript.Run(r.redis,
+		[]string{holdKey(masterId, at), holdIndexKey(masterId, at), userHoldKey(userId)},
+		userId, at.Format("15:04"), userHoldValue(masterId, at),
+	).Err()
+}
+
+// GetHeldSlots returns HH:MM slots of the date held by anyone.
+func (r *SlotHoldsRepo) GetHeldSlots(ctx context.Context, masterId int64, date time.Time) ([]string, error) {
+	key := holdIndexKey(masterId, date)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+
+	if err := r.redis.ZRemRangeByScore(key, "-inf", "("+now).Err(); err != nil {
+		return nil, err
+	}
+	return r.redis.ZRangeByScore(key, redis.ZRangeBy{Min: now, Max: "+inf"}).Result()
+}
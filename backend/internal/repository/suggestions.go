@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strawberry/internal/models"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+type SuggestionsRepo struct {
+	redis *redis.Client
+}
+
+func newRedisSuggestionsRepo(redis *redis.Client) Suggestions {
+	return &SuggestionsRepo{redis: redis}
+}
+
+func suggestionsKey(prefix string, limit int) string {
+	return fmt.Sprintf("suggest:%d:%s", limit, prefix)
+}
+
+// Get returns cached suggestions or ErrNotFound.
+func (r *SuggestionsRepo) Get(ctx context.Context, prefix string, limit int) ([]models.Suggestion, error) {
+	data, err := r.redis.Get(suggestionsKey(prefix, limit)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	var s []models.Suggestion
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *SuggestionsRepo) Set(ctx context.Context, prefix string, limit int, s []models.Suggestion, ttl time.Duration) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return r.redis.Set(suggestionsKey(prefix, limit), data, ttl).Err()
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strawberry/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresTimeBlocksRepository struct {
+	db *pgxpool.Pool
+}
+
+func newPostgresTimeBlocksRepository(db *pgxpool.Pool) TimeBlocks {
+	return &postgresTimeBlocksRepository{db: db}
+}
+
+func (r *postgresTimeBlocksRepository) Create(ctx context.Context, b *models.TimeBlock) (int64, error) {
+	var id int64
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO time_blocks (master_id, starts_at, ends_at, reason, source)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id;
+	`, b.MasterID, b.StartsAt, b.EndsAt, b.Reason, b.Source).Scan(&id)
+	return id, err
+}
+
+func (r *postgresTimeBlocksRepository) GetById(ctx context.Context, id int64) (*models.TimeBlock, error) {
+	var b models.TimeBlock
+	err := r.db.QueryRow(ctx, `
+		SELECT id, master_id, starts_at, ends_at, reason, source
+		FROM time_blocks WHERE id = $1;
+	`, id).Scan(&b.ID, &b.MasterID, &b.StartsAt, &b.EndsAt, &b.Reason, &b.Source)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (r *postgresTimeBlocksRepository) Delete(ctx context.Context, id int64) error {
+	cmdTag, err := r.db.Exec(ctx, `DELETE FROM time_blocks WHERE id = $1;`, id)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ReplaceImported swaps all blocks previously imported for the master with the given ones.
+func (r *postgresTimeBlocksRepository) ReplaceImported(ctx context.Context, masterId int64, blocks []models.TimeBlock) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `DELETE FROM time_blocks WHERE master_id = $1 AND source = $2;`, masterId, models.BlockSourceImport)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"time_blocks"},
+		[]string{"master_id", "starts_at", "ends_at", "reason", "source"},
+		pgx.CopyFromSlice(len(blocks), func(i int) ([]any, error) {
+			b := blocks[i]
+			return []any{masterId, b.StartsAt, b.EndsAt, b.Reason, models.BlockSourceImport}, nil
+		}),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO calendar_imports (master_id, imported_at)
+		VALUES ($1, NOW())
+		ON CONFLICT (master_id) DO UPDATE SET imported_at = EXCLUDED.imported_at;
+	`, masterId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetByRange returns the master's blocks overlapping [from, to).
+func (r *postgresTimeBlocksRepository) GetByRange(ctx context.Context, masterId int64, from, to time.Time) ([]models.TimeBlock, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, master_id, starts_at, ends_at, reason, source
+		FROM time_blocks
+		WHERE master_id = $1 AND starts_at < $3 AND ends_at > $2
+		ORDER BY starts_at;
+	`, masterId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blocks []models.TimeBlock
+	for rows.Next() {
+		var b models.TimeBlock
+		if err := rows.Scan(&b.ID, &b.MasterID, &b.StartsAt, &b.EndsAt, &b.Reason, &b.Source); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks, rows.Err()
+}
+
+func (r *postgresTimeBlocksRepository) GetImport(ctx context.Context, masterId int64) (*models.CalendarImport, error) {
+	imp := &models.CalendarImport{MasterID: masterId}
+	err := r.db.QueryRow(ctx, `
+		SELECT url, imported_at FROM calendar_imports WHERE master_id = $1;
+	`, masterId).Scan(&imp.URL, &imp.ImportedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return imp, nil
+}
+
+func (r *postgresTimeBlocksRepository) SetImportURL(ctx context.Context, masterId int64, url string) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO calendar_imports (master_id, url)
+		VALUES ($1, $2)
+		ON CONFLICT (master_id) DO UPDATE SET url = EXCLUDED.url;
+	`, masterId, url)
+	return err
+}
@@ -3,6 +3,9 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5"
@@ -70,23 +73,77 @@ func (r *postgresUsersRepository) Delete(ctx context.Context, id int64) error {
 	}
 	return nil
 }
-func (r *postgresUsersRepository) GetByFullName(ctx context.Context, fn string) ([]models.User, error) {
-	rows, err := r.db.Query(ctx, `
-		SELECT id, full_name, username, password, email, registered_at, specialization, bio
-		FROM users WHERE full_name = $1;
-	`, fn)
+
+// userSelect reads users together with their denormalized rating counters
+// and the global prior used to compute Bayesian scores, all in one statement.
+var userSelect = fmt.Sprintf(`
+	WITH prior AS (
+		SELECT COALESCE(SUM(rating_sum)::float8 / NULLIF(SUM(review_count), 0), %[1]v) AS mean
+		FROM master_ratings
+	)
+	SELECT u.id, u.full_name, u.username, u.password, u.email, u.registered_at, u.specialization, u.bio,
+		COALESCE(mr.review_count, 0), COALESCE(mr.rating_sum, 0),
+		COALESCE(mr.stars_1, 0), COALESCE(mr.stars_2, 0), COALESCE(mr.stars_3, 0),
+		COALESCE(mr.stars_4, 0), COALESCE(mr.stars_5, 0),
+		p.mean
+	FROM users u
+	LEFT JOIN master_ratings mr ON mr.master_id = u.id
+	CROSS JOIN prior p
+`, models.DefaultRatingPrior)
+
+// ratingScoreOrder sorts by the same Bayesian score RatingSummary.Compute yields.
+var ratingScoreOrder = fmt.Sprintf(`
+	ORDER BY (p.mean * %[1]d + COALESCE(mr.rating_sum, 0)) / (%[1]d + COALESCE(mr.review_count, 0)) DESC,
+		COALESCE(mr.review_count, 0) DESC, u.id
+`, models.RatingPriorWeight)
+
+func scanUser(row pgx.Row) (*models.User, error) {
+	var (
+		u       models.User
+		summary models.RatingSummary
+		prior   float64
+	)
+	err := row.Scan(&u.Id, &u.FullName, &u.Username, &u.Password, &u.Email, &u.RegisteredAt, &u.Specialization, &u.Bio,
+		&summary.Count, &summary.Sum,
+		&summary.Histogram[0], &summary.Histogram[1], &summary.Histogram[2], &summary.Histogram[3], &summary.Histogram[4],
+		&prior)
+	if err != nil {
+		return nil, err
+	}
+	if u.IsMaster() {
+		summary.Compute(prior)
+		u.Rating = &summary
+		u.AverageRating = summary.Mean
+	}
+	return &u, nil
+}
+
+func (r *postgresUsersRepository) queryUsers(ctx context.Context, query string, args ...any) ([]models.User, error) {
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	return collectUsers(rows)
+}
+
+func collectUsers(rows pgx.Rows) ([]models.User, error) {
 	defer rows.Close()
 
 	var users []models.User
 	for rows.Next() {
-		var u models.User
-		if err := rows.Scan(&u.Id, &u.FullName, &u.Username, &u.Password, &u.Email, &u.RegisteredAt, &u.Specialization, &u.Bio); err != nil {
+		u, err := scanUser(rows)
+		if err != nil {
 			return nil, err
 		}
-		users = append(users, u)
+		users = append(users, *u)
+	}
+	return users, rows.Err()
+}
+
+func (r *postgresUsersRepository) GetByFullName(ctx context.Context, fn string) ([]models.User, error) {
+	users, err := r.queryUsers(ctx, userSelect+`WHERE u.full_name = $1;`, fn)
+	if err != nil {
+		return nil, err
 	}
 	if len(users) == 0 {
 		return nil, ErrNoUsers
@@ -95,64 +152,103 @@ func (r *postgresUsersRepository) GetByFullName(ctx context.Context, fn string)
 }
 
 func (r *postgresUsersRepository) GetByUsername(ctx context.Context, un string) (*models.User, error) {
-	row := r.db.QueryRow(ctx, `
-		SELECT id, full_name, username, password, email, registered_at, specialization, bio
-		FROM users WHERE username = $1;
-	`, un)
-
-	var u models.User
-	err := row.Scan(&u.Id, &u.FullName, &u.Username, &u.Password, &u.Email, &u.RegisteredAt, &u.Specialization, &u.Bio)
+	u, err := scanUser(r.db.QueryRow(ctx, userSelect+`WHERE u.username = $1;`, un))
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrNoUsers
 	}
 	if err != nil {
 		return nil, err
 	}
-	return &u, nil
+	return u, nil
 }
 
-func (r *postgresUsersRepository) GetMastersByRating(ctx context.Context) ([]models.User, error) {
-	rows, err := r.db.Query(ctx, `
-		SELECT id, full_name, username, password, email, registered_at, specialization, bio
-		FROM users WHERE specialization != 'user';
-	`)
-	if err != nil {
-		return nil, err
+// masterOrders maps MasterFilter sorts to ORDER BY clauses.
+var masterOrders = map[string]string{
+	models.MasterSortRating:  ratingScoreOrder,
+	models.MasterSortReviews: `ORDER BY COALESCE(mr.review_count, 0) DESC, u.id`,
+	models.MasterSortNewest:  `ORDER BY u.registered_at DESC, u.id DESC`,
+	models.MasterSortName:    `ORDER BY u.full_name, u.id`,
+}
+
+// freeOnDate keeps masters with a working slot on the date that is after now
+// and not a day off, booked or blocked. Date slots override the weekly
+// template like in GetSlotsByDay. Slot holds live in Redis and are not
+// considered.
+var freeOnDate = fmt.Sprintf(`EXISTS (
+	SELECT 1 FROM (
+		SELECT ds.slot FROM date_slots ds WHERE ds.user_id = u.id AND ds.date = %%[1]s
+		UNION ALL
+		SELECT ss.slot FROM schedule_slots ss
+		WHERE ss.user_id = u.id AND ss.day_of_week = %%[2]s
+			AND ss.effective_from = (
+				SELECT MAX(t.effective_from) FROM schedule_templates t
+				WHERE t.user_id = u.id AND t.effective_from <= %%[1]s
+			)
+			AND NOT EXISTS (SELECT 1 FROM date_slots ds WHERE ds.user_id = u.id AND ds.date = %%[1]s)
+	) s
+	WHERE NOT EXISTS (SELECT 1 FROM days_off_dates o WHERE o.user_id = u.id AND o.date = %%[1]s)
+		AND %%[1]s + s.slot > %%[3]s
+		AND NOT EXISTS (
+			SELECT 1 FROM appointments a
+			WHERE a.master_id = u.id AND a.scheduled_at = %%[1]s + s.slot
+				AND a.status NOT IN ('canceled', 'late_canceled')
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM time_blocks b
+			WHERE b.master_id = u.id
+				AND b.starts_at < %%[1]s + s.slot + INTERVAL '%d minutes'
+				AND b.ends_at > %%[1]s + s.slot
+		)
+)`, int(models.AppointmentDuration.Minutes()))
+
+// FindMasters returns a page of masters matching the filter and the number
+// of all matching masters. now is the current naive time in the app's zone.
+func (r *postgresUsersRepository) FindMasters(ctx context.Context, f models.MasterFilter, now time.Time) ([]models.User, int, error) {
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
 	}
-	defer rows.Close()
 
-	var users []models.User
-	for rows.Next() {
-		var u models.User
-		if err := rows.Scan(&u.Id, &u.FullName, &u.Username, &u.Password, &u.Email, &u.RegisteredAt, &u.Specialization, &u.Bio); err != nil {
-			return nil, err
-		}
-		users = append(users, u)
+	where := []string{"u.specialization != '" + userSpec + "'"}
+	if f.Specialization != "" {
+		where = append(where, "u.specialization = "+arg(f.Specialization))
 	}
-	if len(users) == 0 {
-		return nil, ErrNoUsers
+	if f.MinRating > 0 {
+		where = append(where, "COALESCE(mr.rating_sum::float8 / NULLIF(mr.review_count, 0), 0) >= "+arg(f.MinRating))
 	}
-	return users, nil
+	if f.FreeOn != nil {
+		date := arg(f.FreeOn.Format("2006-01-02")) + "::date"
+		dayOfWeek := arg(strings.ToLower(f.FreeOn.Weekday().String()))
+		where = append(where, fmt.Sprintf(freeOnDate, date, dayOfWeek, arg(now)+"::timestamp"))
+	}
+	cond := " WHERE " + strings.Join(where, " AND ")
+
+	var total int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM users u
+		LEFT JOIN master_ratings mr ON mr.master_id = u.id
+	`+cond, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+	if total == 0 || f.Offset >= total {
+		return []models.User{}, total, nil
+	}
+
+	users, err := r.queryUsers(ctx, userSelect+cond+masterOrders[f.Sort]+
+		" LIMIT "+arg(f.Limit)+" OFFSET "+arg(f.Offset), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
 }
 
 func (r *postgresUsersRepository) GetMastersBySpecialization(ctx context.Context, s string) ([]models.User, error) {
-	rows, err := r.db.Query(ctx, `
-		SELECT id, full_name, username, password, email, registered_at, specialization, bio
-		FROM users WHERE specialization = $1;
-	`, s)
+	users, err := r.queryUsers(ctx, userSelect+`WHERE u.specialization = $1`+ratingScoreOrder, s)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var users []models.User
-	for rows.Next() {
-		var u models.User
-		if err := rows.Scan(&u.Id, &u.FullName, &u.Username, &u.Password, &u.Email, &u.RegisteredAt, &u.Specialization, &u.Bio); err != nil {
-			return nil, err
-		}
-		users = append(users, u)
-	}
 	if len(users) == 0 {
 		return nil, ErrNoUsers
 	}
@@ -160,50 +256,106 @@ func (r *postgresUsersRepository) GetMastersBySpecialization(ctx context.Context
 }
 
 func (r *postgresUsersRepository) GetById(ctx context.Context, id int64) (*models.User, error) {
-	row := r.db.QueryRow(ctx, `
-		SELECT id, full_name, username, email, registered_at, specialization, bio
-		FROM users WHERE id = $1;
-	`, id)
-
-	var u models.User
-	err := row.Scan(&u.Id, &u.FullName, &u.Username, &u.Email, &u.RegisteredAt, &u.Specialization, &u.Bio)
+	u, err := scanUser(r.db.QueryRow(ctx, userSelect+`WHERE u.id = $1;`, id))
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrNoUsers
 	}
 	if err != nil {
 		return nil, err
 	}
-	return &u, nil
+	u.Password = ""
+	return u, nil
 }
 
-func (r *postgresUsersRepository) SearchUsers(ctx context.Context, query string) ([]models.User, error) {
-	sqlQuery := `
-        SELECT id, full_name, email, username, password, registered_at, specialization, bio
-        FROM users
-        WHERE full_name ILIKE $1 OR username ILIKE $1 OR specialization ILIKE $1
-    `
-	likeQuery := "%" + query + "%"
+// searchTsQuery ORs the query stemmed as Russian, as English and as is, so
+// that either language and usernames match.
+const searchTsQuery = `(websearch_to_tsquery('russian', $1) || websearch_to_tsquery('english', $1) || websearch_to_tsquery('simple', $1))`
+
+// searchOrder blends text relevance, the better of full-text rank and
+// trigram similarity, with the Bayesian rating score scaled to 0..1.
+var searchOrder = fmt.Sprintf(`
+	ORDER BY %[1]v * GREATEST(ts_rank_cd(u.search_vector, %[2]s, 32), word_similarity(lower($1), u.search_text))
+		+ %[3]v * (p.mean * %[4]d + COALESCE(mr.rating_sum, 0)) / (%[4]d + COALESCE(mr.review_count, 0)) / 5 DESC,
+		u.id
+`, 1-models.SearchRatingWeight, searchTsQuery, models.SearchRatingWeight, models.RatingPriorWeight)
+
+// SearchUsers finds masters by name, username, specialization and bio. Words
+// are matched by their stems; misspelled ones by trigram similarity to the
+// name, username and specialization.
+func (r *postgresUsersRepository) SearchUsers(ctx context.Context, text string, limit, offset int) ([]models.User, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, fmt.Sprintf(`SET LOCAL pg_trgm.word_similarity_threshold = %v`, models.SearchFuzzyThreshold))
+	if err != nil {
+		return nil, err
+	}
 
-	rows, err := r.db.Query(ctx, sqlQuery, likeQuery)
+	rows, err := tx.Query(ctx, userSelect+`
+		WHERE u.specialization != '`+userSpec+`'
+			AND (u.search_vector @@ `+searchTsQuery+` OR lower($1) <% u.search_text)
+	`+searchOrder+`
+		LIMIT $2 OFFSET $3
+	`, text, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	users, err := collectUsers(rows)
+	if err != nil {
+		return nil, err
+	}
+	return users, tx.Commit(ctx)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Suggest returns masters whose name or username and specializations that
+// have a word starting with prefix, most popular first. A master's
+// popularity is their review count; a specialization's is the number of its
+// masters plus their reviews. The trigram index on search_text serves the
+// LIKE patterns. prefix must be lowercase.
+func (r *postgresUsersRepository) Suggest(ctx context.Context, prefix string, limit int) ([]models.Suggestion, error) {
+	escaped := likeEscaper.Replace(prefix)
+	start, word := escaped+"%", "% "+escaped+"%"
+
+	rows, err := r.db.Query(ctx, `
+		WITH m AS (
+			SELECT u.full_name, u.username, u.specialization, COALESCE(mr.review_count, 0) AS popularity
+			FROM users u
+			LEFT JOIN master_ratings mr ON mr.master_id = u.id
+			WHERE u.specialization != '`+userSpec+`'
+				AND (u.search_text LIKE $1 OR u.search_text LIKE $2)
+		)
+		SELECT kind, text, username FROM (
+			SELECT '`+models.SuggestionKindMaster+`' AS kind, full_name AS text, username, popularity
+			FROM m
+			WHERE lower(full_name) LIKE $1 OR lower(full_name) LIKE $2 OR lower(username) LIKE $1
+			UNION ALL
+			SELECT '`+models.SuggestionKindSpecialization+`', specialization, '', SUM(popularity + 1)
+			FROM m
+			WHERE lower(specialization) LIKE $1 OR lower(specialization) LIKE $2
+			GROUP BY specialization
+		) s
+		ORDER BY popularity DESC, text
+		LIMIT $3
+	`, start, word, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var users []models.User
+	var suggestions []models.Suggestion
 	for rows.Next() {
-		var u models.User
-		err := rows.Scan(&u.Id, &u.FullName, &u.Email, &u.Username, &u.Password, &u.RegisteredAt, &u.Specialization, &u.Bio)
-		if err != nil {
+		var sg models.Suggestion
+		if err := rows.Scan(&sg.Kind, &sg.Text, &sg.Username); err != nil {
 			return nil, err
 		}
-		if u.Specialization == userSpec {
-			continue
-		}
-		users = append(users, u)
+		suggestions = append(suggestions, sg)
 	}
-
-	return users, nil
+	return suggestions, rows.Err()
 }
 
 func (r *postgresUsersRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
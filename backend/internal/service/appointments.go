@@ -17,17 +17,29 @@ import (
 	"go.uber.org/zap"
 )
 
+const defaultHoldTTL = 5 * time.Minute
+
 type AppointmentsService struct {
-	r    *repository.Repository
-	rmq  *rabbitmq.MQConnection
-	mail mail.MailClient
+	r       *repository.Repository
+	rmq     *rabbitmq.MQConnection
+	mail    mail.MailClient
+	holdTTL time.Duration
+	loc     *time.Location
 }
 
-func newAppointmentsService(r *repository.Repository, rmq *rabbitmq.MQConnection, mail mail.MailClient) Appointments {
+func newAppointmentsService(r *repository.Repository, rmq *rabbitmq.MQConnection, mail mail.MailClient, holdTTL time.Duration, loc *time.Location) Appointments {
+	if holdTTL <= 0 {
+		holdTTL = defaultHoldTTL
+	}
+	if loc == nil {
+		loc = time.Local
+	}
 	return &AppointmentsService{
-		r:    r,
-		rmq:  rmq,
-		mail: mail,
+		r:       r,
+		rmq:     rmq,
+		mail:    mail,
+		holdTTL: holdTTL,
+		loc:     loc,
 	}
 }
 
@@ -36,17 +48,43 @@ var (
 	ErrAppointmentConflict = errors.New("appointment conflict")
 	ErrInvalidAppointment  = errors.New("invalid appointment data")
 	ErrMasterUnavaliable   = errors.New("master unavaliable")
+	ErrAppointmentStarted  = errors.New("appointment already started")
+	ErrAppointmentCanceled = errors.New("appointment already canceled")
+	ErrNotMaster           = errors.New("only masters can do that")
+	ErrSlotHeld            = errors.New("slot is held by another client")
 )
 
+// OccurrenceConflictError lists occurrences of a recurring appointment that can't be booked.
+type OccurrenceConflictError struct {
+	Conflicts []models.OccurrenceConflict
+}
+
+func (e *OccurrenceConflictError) Error() string {
+	return fmt.Sprintf("%d occurrences conflict with the master's schedule", len(e.Conflicts))
+}
+
 func (s *AppointmentsService) Create(ctx context.Context, a *models.Appointment) (int64, error) {
 	ctx = logger.WithLogger(ctx)
 	l := logger.FromContext(ctx)
 
+	status, err := s.initialStatus(ctx, a.MasterID, a.UserID)
+	if err != nil {
+		l.Error("failed to resolve initial appointment status", zap.Error(err))
+		return 0, ErrInternal
+	}
+	a.Status = status
+
 	if err := a.Validate(); err != nil {
 		l.Warn("invalid appointment data", zap.Error(err))
 		return 0, ValidationError{Msg: err.Error()}
 	}
 
+	if held, err := s.heldByOther(ctx, a.MasterID, a.ScheduledAt, a.UserID); err != nil {
+		return 0, err
+	} else if held {
+		return 0, ErrSlotHeld
+	}
+
 	id, err := s.r.Appointments.Create(ctx, a)
 	if err != nil {
 		switch {
@@ -60,6 +98,7 @@ func (s *AppointmentsService) Create(ctx context.Context, a *models.Appointment)
 			return 0, ErrInternal
 		}
 	}
+	s.releaseHold(ctx, a.MasterID, a.ScheduledAt, a.UserID)
 
 	go func(id int64, a *models.Appointment) {
 		bgCtx := context.Background()
@@ -170,16 +209,71 @@ func (s *AppointmentsService) Delete(ctx context.Context, id int64, userId int64
 		l.Warn("unauthorized", zap.Int64("user_id", userId))
 		return ErrUnauthorized
 	}
+	if a.IsCanceled() {
+		return ErrAppointmentCanceled
+	}
+
+	now := time.Now()
+	if !now.Before(a.ScheduledAt) {
+		l.Warn("can't cancel started appointment", zap.Int64("id", id))
+		return ErrAppointmentStarted
+	}
+
+	policy, err := s.GetPolicy(ctx, a.MasterID)
+	if err != nil {
+		return err
+	}
+	if err := s.cancel(ctx, a, userId, policy, now); err != nil {
+		return err
+	}
 
-	err = s.r.Appointments.Delete(ctx, id)
+	us, err := s.r.Users.GetById(ctx, a.UserID)
+	if err != nil {
+		l.Error("failed to get user", zap.Error(err))
+		return ErrInternal
+	}
+	master, err := s.r.Users.GetById(ctx, a.MasterID)
+	if err != nil {
+		l.Error("failed to get master", zap.Error(err))
+		return ErrInternal
+	}
+
+	msg := fmt.Sprintf("Похоже что %s отменил(а) вашу запись в %s...Попробуете записаться в другое время?", master.FullName, a.ScheduledAt.Format("2006-01-02 15:04"))
+
+	go func() {
+		bgCtx := context.Background()
+		bgCtx = logger.WithLogger(bgCtx)
+		bgLog := logger.FromContext(bgCtx)
+
+		if err := s.mail.Send(us.Email, "вашу запись отменили :(", msg); err != nil {
+			bgLog.Error("failed to send mail", zap.Error(err))
+		}
+	}()
+
+	return nil
+}
+
+// cancel marks a single appointment as canceled, or late_canceled when a client
+// cancels after the free-cancel deadline, and notifies the bot.
+func (s *AppointmentsService) cancel(ctx context.Context, a *models.Appointment, userId int64, policy *models.CancellationPolicy, now time.Time) error {
+	l := logger.FromContext(ctx)
+	id := int64(a.ID)
+
+	status := models.StatusCanceled
+	if a.UserID == userId && policy.IsLateCancel(a.ScheduledAt, now) {
+		status = models.StatusLateCanceled
+	}
+
+	err := s.r.Appointments.Cancel(ctx, id, status, userId)
 	if err != nil {
 		if errors.Is(err, repository.ErrNoAppointments) {
 			l.Warn("appointment not found", zap.Int64("id", id))
 			return ErrAppointmentNotFound
 		}
-		l.Error("failed to delete appointment", zap.Error(err))
+		l.Error("failed to cancel appointment", zap.Error(err))
 		return ErrInternal
 	}
+	l.Info("appointment canceled", zap.Int64("id", id), zap.String("status", status))
 
 	go func(id int64, a *models.Appointment) {
 		bgCtx := context.Background()
@@ -191,51 +285,286 @@ func (s *AppointmentsService) Delete(ctx context.Context, id int64, userId int64
 		}
 	}(id, a)
 
-	us, err := s.r.Users.GetById(ctx, a.UserID)
+	return nil
+}
+
+func (s *AppointmentsService) CreateRecurring(ctx context.Context, a *models.Appointment, rule *models.Recurrence) (*models.AppointmentSeries, []int64, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	if err := rule.Validate(a.ScheduledAt); err != nil {
+		return nil, nil, ValidationError{Msg: err.Error()}
+	}
+
+	status, err := s.initialStatus(ctx, a.MasterID, a.UserID)
 	if err != nil {
-		l.Error("failed to get user", zap.Error(err))
+		l.Error("failed to resolve initial appointment status", zap.Error(err))
+		return nil, nil, ErrInternal
+	}
+	a.Status = status
+
+	if err := a.Validate(); err != nil {
+		l.Warn("invalid appointment data", zap.Error(err))
+		return nil, nil, ValidationError{Msg: err.Error()}
+	}
+
+	times := rule.Occurrences(a.ScheduledAt)
+	if len(times) < 2 {
+		return nil, nil, ValidationError{Msg: "recurrence must produce at least two occurrences"}
+	}
+
+	var (
+		apts      []models.Appointment
+		conflicts []models.OccurrenceConflict
+	)
+	for _, t := range times {
+		err := s.r.Appointments.CheckAvailability(ctx, a.MasterID, t)
+		switch {
+		case err == nil:
+		case errors.Is(err, repository.ErrMasterUnavailable):
+			conflicts = append(conflicts, models.OccurrenceConflict{Time: t, Reason: ErrMasterUnavaliable.Error()})
+		case errors.Is(err, repository.ErrAppointmentConflict):
+			conflicts = append(conflicts, models.OccurrenceConflict{Time: t, Reason: ErrAppointmentConflict.Error()})
+		default:
+			l.Error("failed to check availability", zap.Time("time", t), zap.Error(err))
+			return nil, nil, ErrInternal
+		}
+		if err == nil {
+			if held, err := s.heldByOther(ctx, a.MasterID, t, a.UserID); err != nil {
+				return nil, nil, err
+			} else if held {
+				conflicts = append(conflicts, models.OccurrenceConflict{Time: t, Reason: ErrSlotHeld.Error()})
+			}
+		}
+		apts = append(apts, models.Appointment{
+			UserID:      a.UserID,
+			MasterID:    a.MasterID,
+			ScheduledAt: t,
+			Status:      a.Status,
+		})
+	}
+	if len(conflicts) > 0 {
+		l.Warn("recurring appointment has conflicts", zap.Int("conflicts", len(conflicts)))
+		return nil, nil, &OccurrenceConflictError{Conflicts: conflicts}
+	}
+
+	series := &models.AppointmentSeries{
+		UserID:    a.UserID,
+		MasterID:  a.MasterID,
+		Frequency: rule.Frequency,
+		Count:     rule.Count,
+		Until:     rule.Until,
+	}
+	ids, err := s.r.Appointments.CreateSeries(ctx, series, apts)
+	if err != nil {
+		if errors.Is(err, repository.ErrAppointmentConflict) {
+			l.Warn("appointment conflict", zap.Error(err))
+			return nil, nil, ErrAppointmentConflict
+		}
+		l.Error("failed to create appointment series", zap.Error(err))
+		return nil, nil, ErrInternal
+	}
+	s.releaseHold(ctx, a.MasterID, a.ScheduledAt, a.UserID)
+
+	for i := range apts {
+		go func(id int64, a *models.Appointment) {
+			bgCtx := context.Background()
+			bgCtx = logger.WithLogger(bgCtx)
+			bgLog := logger.FromContext(bgCtx)
+
+			if err := s.publishAppointmentCreated(bgCtx, id, a); err != nil {
+				bgLog.Error("cannot publish appointment to rmq (async)", zap.Error(err))
+			}
+		}(ids[i], &apts[i])
+	}
+
+	l.Info("appointment series created", zap.Int64("series_id", series.ID), zap.Int("occurrences", len(ids)))
+	return series, ids, nil
+}
+
+// CancelSeries cancels every upcoming occurrence of the series.
+// Past and already canceled occurrences are left untouched.
+func (s *AppointmentsService) CancelSeries(ctx context.Context, seriesId int64, userId int64) error {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	series, err := s.r.Appointments.GetSeriesById(ctx, seriesId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoAppointments) {
+			return ErrAppointmentNotFound
+		}
+		l.Error("failed to get appointment series", zap.Error(err))
 		return ErrInternal
 	}
-	master, err := s.r.Users.GetById(ctx, a.MasterID)
+	if series.UserID != userId && series.MasterID != userId {
+		l.Warn("unauthorized", zap.Int64("user_id", userId))
+		return ErrUnauthorized
+	}
+
+	apts, err := s.r.Appointments.GetBySeriesId(ctx, seriesId)
 	if err != nil {
-		l.Error("failed to get master", zap.Error(err))
+		l.Error("failed to get series appointments", zap.Error(err))
 		return ErrInternal
 	}
+	policy, err := s.GetPolicy(ctx, series.MasterID)
+	if err != nil {
+		return err
+	}
 
-	msg := fmt.Sprintf("Похоже что %s отменил(а) вашу запись в %s...Попробуете записаться в другое время?", master.FullName, a.ScheduledAt.Format("2006-01-02 15:04"))
+	now := time.Now()
+	canceled := 0
+	for i := range apts {
+		a := &apts[i]
+		if a.IsCanceled() || !now.Before(a.ScheduledAt) {
+			continue
+		}
+		if err := s.cancel(ctx, a, userId, policy, now); err != nil {
+			return err
+		}
+		canceled++
+	}
+	if canceled == 0 {
+		return ErrAppointmentCanceled
+	}
 
-	go func() {
-		bgCtx := context.Background()
-		bgCtx = logger.WithLogger(bgCtx)
-		bgLog := logger.FromContext(bgCtx)
+	l.Info("appointment series canceled", zap.Int64("series_id", seriesId), zap.Int("canceled", canceled))
+	return nil
+}
 
-		if err := s.mail.Send(us.Email, "вашу запись отменили :(", msg); err != nil {
-			bgLog.Error("failed to send mail", zap.Error(err))
+func (s *AppointmentsService) UpdateStatus(ctx context.Context, id int64, masterId int64, status string) error {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	a, err := s.r.Appointments.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoAppointments) {
+			return ErrAppointmentNotFound
 		}
-	}()
+		l.Error("failed to get appointment", zap.Error(err))
+		return ErrInternal
+	}
+	if a.MasterID != masterId {
+		l.Warn("unauthorized", zap.Int64("user_id", masterId))
+		return ErrUnauthorized
+	}
+	if err := a.CanTransitionTo(status, time.Now()); err != nil {
+		return ValidationError{Msg: err.Error()}
+	}
 
+	if err := s.r.Appointments.UpdateStatus(ctx, id, status); err != nil {
+		l.Error("failed to update appointment status", zap.Error(err))
+		return ErrInternal
+	}
+	l.Info("appointment status updated", zap.Int64("id", id), zap.String("status", status))
 	return nil
 }
 
-func (s *AppointmentsService) GetByUserId(ctx context.Context, id int64) ([]models.Appointment, error) {
+func (s *AppointmentsService) GetPolicy(ctx context.Context, masterId int64) (*models.CancellationPolicy, error) {
+	l := logger.FromContext(ctx)
+
+	p, err := s.r.CancellationPolicies.Get(ctx, masterId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return models.DefaultCancellationPolicy(masterId), nil
+		}
+		l.Error("failed to get cancellation policy", zap.Int64("master_id", masterId), zap.Error(err))
+		return nil, ErrInternal
+	}
+	return p, nil
+}
+
+func (s *AppointmentsService) SetPolicy(ctx context.Context, p *models.CancellationPolicy) error {
 	ctx = logger.WithLogger(ctx)
 	l := logger.FromContext(ctx)
 
-	appointments, err := s.r.Appointments.GetByUserId(ctx, id)
+	if err := ensureMaster(ctx, s.r, p.MasterID); err != nil {
+		return err
+	}
+	if err := p.Validate(); err != nil {
+		return ValidationError{Msg: err.Error()}
+	}
+	if err := s.r.CancellationPolicies.Upsert(ctx, p); err != nil {
+		l.Error("failed to save cancellation policy", zap.Int64("master_id", p.MasterID), zap.Error(err))
+		return ErrInternal
+	}
+	return nil
+}
+
+func (s *AppointmentsService) GetClientStats(ctx context.Context, masterId int64, clientId int64) (*models.ClientStats, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	if err := ensureMaster(ctx, s.r, masterId); err != nil {
+		return nil, err
+	}
+	booked, err := s.r.Appointments.HasBooked(ctx, clientId, masterId)
 	if err != nil {
-		l.Error("failed to get appointments by user ID", zap.Error(err))
+		l.Error("failed to check client bookings", zap.Int64("client_id", clientId), zap.Error(err))
 		return nil, ErrInternal
 	}
+	if !booked {
+		return nil, ErrNotYourClient
+	}
+
+	stats, err := s.r.Appointments.GetClientStats(ctx, clientId)
+	if err != nil {
+		l.Error("failed to get client stats", zap.Int64("client_id", clientId), zap.Error(err))
+		return nil, ErrInternal
+	}
+	return stats, nil
+}
+
+// initialStatus confirms bookings right away if the master's policy says so,
+// unless the master wants to review clients who already reached their
+// no-show threshold.
+func (s *AppointmentsService) initialStatus(ctx context.Context, masterId, clientId int64) (string, error) {
+	policy, err := s.GetPolicy(ctx, masterId)
+	if err != nil {
+		return "", err
+	}
+	if !policy.AutoConfirm {
+		return models.StatusPending, nil
+	}
+	if !policy.RequireConfirmation {
+		return models.StatusConfirmed, nil
+	}
+	stats, err := s.r.Appointments.GetClientStats(ctx, clientId)
+	if err != nil {
+		return "", err
+	}
+	if policy.NeedsConfirmation(stats) {
+		return models.StatusPending, nil
+	}
+	return models.StatusConfirmed, nil
+}
+
+func ensureMaster(ctx context.Context, r *repository.Repository, userId int64) error {
+	l := logger.FromContext(ctx)
 
-	var validAppointments []models.Appointment
-	for _, appt := range appointments {
-		if err := appt.Validate(); err != nil {
-			l.Info("failed validating", zap.Error(err))
-		} else {
-			validAppointments = append(validAppointments, appt)
+	u, err := r.Users.GetById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoUsers) {
+			return ErrUserNotFound
 		}
+		l.Error("failed to get user", zap.Error(err))
+		return ErrInternal
 	}
-	return validAppointments, nil
+	if !u.IsMaster() {
+		return ErrNotMaster
+	}
+	return nil
+}
+
+func (s *AppointmentsService) GetByUserId(ctx context.Context, id int64) ([]models.Appointment, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	appointments, err := s.r.Appointments.GetByUserId(ctx, id)
+	if err != nil {
+		l.Error("failed to get appointments by user ID", zap.Error(err))
+		return nil, ErrInternal
+	}
+	return appointments, nil
 }
 
 func (s *AppointmentsService) GetByMasterId(ctx context.Context, id int64) ([]models.Appointment, error) {
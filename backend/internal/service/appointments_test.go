@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"strawberry/internal/models"
+	"strawberry/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitialStatus(t *testing.T) {
+	policies := &policiesRepoMock{}
+	policies.On("Get", mock.Anything, int64(2)).Return(nil, repository.ErrNotFound)
+	policies.On("Get", mock.Anything, int64(3)).Return(models.DefaultCancellationPolicy(3), nil)
+	policies.On("Get", mock.Anything, int64(4)).Return(&models.CancellationPolicy{MasterID: 4, AutoConfirm: true}, nil)
+
+	s := &AppointmentsService{r: &repository.Repository{CancellationPolicies: policies}}
+
+	status, err := s.initialStatus(context.Background(), 2, 1)
+	require.NoError(t, err)
+	require.Equal(t, models.StatusPending, status)
+
+	status, err = s.initialStatus(context.Background(), 3, 1)
+	require.NoError(t, err)
+	require.Equal(t, models.StatusPending, status)
+
+	status, err = s.initialStatus(context.Background(), 4, 1)
+	require.NoError(t, err)
+	require.Equal(t, models.StatusConfirmed, status)
+}
+
+func TestGetClientStats_NotYourClient(t *testing.T) {
+	users := &usersRepoMock{}
+	appointments := &appointmentsRepoMock{}
+	users.On("GetById", mock.Anything, int64(3)).Return(&models.User{Id: 3, Specialization: "nails"}, nil)
+	appointments.On("HasBooked", mock.Anything, int64(7), int64(3)).Return(false, nil)
+
+	s := &AppointmentsService{r: &repository.Repository{Users: users, Appointments: appointments}}
+
+	_, err := s.GetClientStats(context.Background(), 3, 7)
+
+	require.ErrorIs(t, err, ErrNotYourClient)
+	appointments.AssertExpectations(t)
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strawberry/internal/models"
+	"strawberry/internal/repository"
+	"strawberry/pkg/logger"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	alternativesSameDay = 3
+	alternativesTotal   = 5
+	alternativesHorizon = 14
+	otherMastersLimit   = 5
+
+	alternativeTimeLayout = "2006-01-02 15:04"
+)
+
+// SuggestAlternatives returns the nearest free slots of the master: the closest
+// ones on the requested day first, then the earliest ones on the following days.
+// With includeOthers it adds the nearest slot of the same day for other masters
+// of the same specialization.
+func (s *AppointmentsService) SuggestAlternatives(ctx context.Context, userId int64, masterId int64, at time.Time, includeOthers bool) ([]models.SlotAlternative, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	now := naiveTime(time.Now(), s.loc)
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+
+	var alternatives []models.SlotAlternative
+	for i := 0; i < alternativesHorizon && len(alternatives) < alternativesTotal; i++ {
+		d := day.AddDate(0, 0, i)
+		free, err := s.freeSlots(ctx, masterId, d, now)
+		if err != nil {
+			l.Error("failed to get free slots", zap.Int64("master_id", masterId), zap.Time("day", d), zap.Error(err))
+			return nil, ErrInternal
+		}
+
+		if i == 0 {
+			free = nearest(free, at)
+			if len(free) > alternativesSameDay {
+				free = free[:alternativesSameDay]
+			}
+		}
+		for _, t := range free {
+			if len(alternatives) == alternativesTotal {
+				break
+			}
+			if !t.Equal(at) {
+				alternatives = append(alternatives, models.SlotAlternative{MasterID: masterId, Time: t.Format(alternativeTimeLayout)})
+			}
+		}
+	}
+
+	if includeOthers {
+		others, err := s.otherMastersAlternatives(ctx, userId, masterId, at, now)
+		if err != nil {
+			return nil, err
+		}
+		alternatives = append(alternatives, others...)
+	}
+	return alternatives, nil
+}
+
+func (s *AppointmentsService) otherMastersAlternatives(ctx context.Context, userId, masterId int64, at, now time.Time) ([]models.SlotAlternative, error) {
+	l := logger.FromContext(ctx)
+
+	master, err := s.r.Users.GetById(ctx, masterId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoUsers) {
+			return nil, nil
+		}
+		l.Error("failed to get master", zap.Int64("master_id", masterId), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	masters, err := s.r.Users.GetMastersBySpecialization(ctx, master.Specialization)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoUsers) {
+			return nil, nil
+		}
+		l.Error("failed to get masters by specialization", zap.String("specialization", master.Specialization), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+	type candidate struct {
+		alt  models.SlotAlternative
+		diff time.Duration
+	}
+	var candidates []candidate
+	checked := 0
+	for _, m := range masters {
+		if m.Id == masterId || m.Id == userId {
+			continue
+		}
+		if checked == otherMastersLimit {
+			break
+		}
+		checked++
+
+		free, err := s.freeSlots(ctx, m.Id, day, now)
+		if err != nil {
+			l.Error("failed to get free slots", zap.Int64("master_id", m.Id), zap.Error(err))
+			return nil, ErrInternal
+		}
+		if free = nearest(free, at); len(free) == 0 {
+			continue
+		}
+		candidates = append(candidates, candidate{
+			alt: models.SlotAlternative{
+				MasterID:   m.Id,
+				MasterName: m.FullName,
+				Time:       free[0].Format(alternativeTimeLayout),
+			},
+			diff: absDuration(free[0].Sub(at)),
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].diff < candidates[j].diff })
+	res := make([]models.SlotAlternative, 0, len(candidates))
+	for _, c := range candidates {
+		res = append(res, c.alt)
+	}
+	return res, nil
+}
+
+// freeSlots returns the master's bookable slots on the day after now: working
+// slots that are not a day off, booked, blocked or held.
+func (s *AppointmentsService) freeSlots(ctx context.Context, masterId int64, day, now time.Time) ([]time.Time, error) {
+	daysOff, err := s.r.Schedules.GetDaysOff(ctx, masterId)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range daysOff {
+		if d.Format(DateFormat) == day.Format(DateFormat) {
+			return nil, nil
+		}
+	}
+
+	slots, err := s.r.Schedules.GetSlotsByDay(ctx, masterId, day, strings.ToLower(day.Weekday().String()))
+	if err != nil || len(slots) == 0 {
+		return nil, err
+	}
+
+	taken := map[string]bool{}
+	booked, err := s.r.Appointments.GetByDate(ctx, masterId, day)
+	if err != nil && !errors.Is(err, repository.ErrNoAppointments) {
+		return nil, err
+	}
+	for _, a := range booked {
+		taken[a.ScheduledAt.Format("15:04")] = true
+	}
+	held, err := s.r.SlotHolds.GetHeldSlots(ctx, masterId, day)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range held {
+		taken[h] = true
+	}
+	blocks, err := s.r.TimeBlocks.GetByRange(ctx, masterId, day, day.AddDate(0, 0, 1).Add(models.AppointmentDuration))
+	if err != nil {
+		return nil, err
+	}
+
+	var free []time.Time
+	for _, slot := range slots {
+		t := day.Add(time.Duration(slot.Hour())*time.Hour + time.Duration(slot.Minute())*time.Minute)
+		if !t.After(now) || taken[slot.Format("15:04")] {
+			continue
+		}
+		blocked := false
+		for _, b := range blocks {
+			if b.Overlaps(t, t.Add(models.AppointmentDuration)) {
+				blocked = true
+				break
+			}
+		}
+		if !blocked {
+			free = append(free, t)
+		}
+	}
+	return free, nil
+}
+
+// nearest sorts times by distance to at, earlier first on ties.
+func nearest(times []time.Time, at time.Time) []time.Time {
+	res := append([]time.Time(nil), times...)
+	sort.SliceStable(res, func(i, j int) bool {
+		di, dj := absDuration(res[i].Sub(at)), absDuration(res[j].Sub(at))
+		if di != dj {
+			return di < dj
+		}
+		return res[i].Before(res[j])
+	})
+	return res
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"strawberry/internal/models"
+	"strawberry/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestAlternatives_NoBookings(t *testing.T) {
+	schedules := &schedulesRepoMock{}
+	appointments := &appointmentsRepoMock{}
+	holds := &slotHoldsRepoMock{}
+	blocks := &timeBlocksRepoMock{}
+
+	slot := func(h int) time.Time { return time.Date(0, 1, 1, h, 0, 0, 0, time.UTC) }
+	schedules.On("GetDaysOff", mock.Anything, int64(2)).Return([]time.Time{}, nil)
+	schedules.On("GetSlotsByDay", mock.Anything, int64(2), mock.Anything, mock.Anything).
+		Return([]time.Time{slot(10), slot(11), slot(12)}, nil)
+	appointments.On("GetByDate", mock.Anything, int64(2), mock.Anything).Return(nil, repository.ErrNoAppointments)
+	holds.On("GetHeldSlots", mock.Anything, int64(2), mock.Anything).Return([]string{}, nil)
+	blocks.On("GetByRange", mock.Anything, int64(2), mock.Anything, mock.Anything).Return([]models.TimeBlock{}, nil)
+
+	s := &AppointmentsService{
+		r: &repository.Repository{
+			Schedules:    schedules,
+			Appointments: appointments,
+			SlotHolds:    holds,
+			TimeBlocks:   blocks,
+		},
+		loc: time.UTC,
+	}
+
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1)
+	at := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 10, 0, 0, 0, time.UTC)
+
+	alternatives, err := s.SuggestAlternatives(context.Background(), 1, 2, at, false)
+
+	require.NoError(t, err)
+	require.Len(t, alternatives, alternativesTotal)
+	require.Equal(t, at.Add(time.Hour).Format(alternativeTimeLayout), alternatives[0].Time)
+	require.Equal(t, at.Add(2*time.Hour).Format(alternativeTimeLayout), alternatives[1].Time)
+	appointments.AssertExpectations(t)
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strawberry/internal/models"
+	"strawberry/internal/repository"
+	"strawberry/pkg/ical"
+	"strawberry/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	feedTokenBytes = 24
+	feedHistory    = 90 * 24 * time.Hour
+	feedProdID     = "-//strawberry//appointments//RU"
+)
+
+var ErrInvalidFeedToken = errors.New("invalid calendar feed token")
+
+type CalendarService struct {
+	repo *repository.Repository
+	loc  *time.Location
+}
+
+func newCalendarService(r *repository.Repository, loc *time.Location) *CalendarService {
+	if loc == nil {
+		loc = time.Local
+	}
+	return &CalendarService{repo: r, loc: loc}
+}
+
+// GetFeedToken returns the user's feed token, creating one on first use.
+func (s *CalendarService) GetFeedToken(ctx context.Context, userId int64) (string, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	token, err := s.repo.CalendarFeeds.GetToken(ctx, userId)
+	if err == nil {
+		return token, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		l.Error("failed to get feed token", zap.Int64("user_id", userId), zap.Error(err))
+		return "", ErrInternal
+	}
+	return s.RotateFeedToken(ctx, userId)
+}
+
+// RotateFeedToken replaces the user's feed token, so previously shared feed links stop working.
+func (s *CalendarService) RotateFeedToken(ctx context.Context, userId int64) (string, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	buf := make([]byte, feedTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		l.Error("failed to generate feed token", zap.Error(err))
+		return "", ErrInternal
+	}
+	token := hex.EncodeToString(buf)
+
+	if err := s.repo.CalendarFeeds.SetToken(ctx, userId, token); err != nil {
+		l.Error("failed to save feed token", zap.Int64("user_id", userId), zap.Error(err))
+		return "", ErrInternal
+	}
+	l.Info("calendar feed token rotated", zap.Int64("user_id", userId))
+	return token, nil
+}
+
+// Feed renders the appointments of the token owner as an iCalendar document,
+// either the ones booked with them as a master or the ones they booked as a client.
+func (s *CalendarService) Feed(ctx context.Context, token string, asMaster bool) ([]byte, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	userId, err := s.repo.CalendarFeeds.GetUserIdByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidFeedToken
+		}
+		l.Error("failed to resolve feed token", zap.Error(err))
+		return nil, ErrInternal
+	}
+	owner, err := s.repo.Users.GetById(ctx, userId)
+	if err != nil {
+		l.Error("failed to get feed owner", zap.Int64("user_id", userId), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	since := time.Now().Add(-feedHistory)
+	var apts []models.Appointment
+	if asMaster {
+		apts, err = s.repo.Appointments.GetByMasterIdSince(ctx, userId, since)
+	} else {
+		apts, err = s.repo.Appointments.GetByUserIdSince(ctx, userId, since)
+	}
+	if err != nil {
+		l.Error("failed to get appointments for feed", zap.Int64("user_id", userId), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	names := make(map[int64]string)
+	cal := &ical.Calendar{
+		ProdID:   feedProdID,
+		Name:     fmt.Sprintf("Strawberry: %s", owner.FullName),
+		Location: s.loc,
+	}
+	for _, a := range apts {
+		counterpart := a.MasterID
+		if asMaster {
+			counterpart = a.UserID
+		}
+		name, ok := names[counterpart]
+		if !ok {
+			if u, err := s.repo.Users.GetById(ctx, counterpart); err == nil {
+				name = u.FullName
+			}
+			names[counterpart] = name
+		}
+		cal.Events = append(cal.Events, s.appointmentEvent(&a, name, asMaster))
+	}
+
+	var buf bytes.Buffer
+	if err := cal.Encode(&buf); err != nil {
+		l.Error("failed to encode calendar", zap.Error(err))
+		return nil, ErrInternal
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *CalendarService) appointmentEvent(a *models.Appointment, counterpart string, asMaster bool) ical.Event {
+	start := wallTime(a.ScheduledAt, s.loc)
+	ev := ical.Event{
+		UID:     fmt.Sprintf("appointment-%d@strawberry", a.ID),
+		Start:   start,
+		End:     start.Add(models.AppointmentDuration),
+		Created: wallTime(a.CreatedAt, s.loc),
+		Status:  ical.StatusConfirmed,
+	}
+	if asMaster {
+		ev.Summary = fmt.Sprintf("Клиент: %s", counterpart)
+	} else {
+		ev.Summary = fmt.Sprintf("Запись к мастеру %s", counterpart)
+	}
+
+	switch {
+	case a.IsCanceled():
+		ev.Status = ical.StatusCancelled
+		ev.Sequence = 1
+		if a.CanceledAt != nil {
+			ev.LastModified = wallTime(*a.CanceledAt, s.loc)
+		}
+	case a.Status == models.StatusPending:
+		ev.Status = ical.StatusTentative
+		ev.Description = "Ожидает подтверждения мастером"
+	case a.Status == models.StatusNoShow:
+		ev.Description = "Клиент не пришёл"
+	}
+	return ev
+}
+
+// wallTime interprets a TIMESTAMP WITHOUT TIME ZONE value as wall clock time in loc.
+func wallTime(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), 0, loc)
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strawberry/internal/models"
+	"strawberry/internal/repository"
+	"strawberry/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrNotAppointmentMaster = errors.New("only the master of the appointment can rate the client")
+	ErrClientNotRateable    = errors.New("only clients of completed appointments can be rated")
+	ErrNotYourClient        = errors.New("client has never booked with this master")
+)
+
+// RateClient saves the master's private rating of the client of a completed
+// appointment. Rating the same appointment again replaces the rating.
+func (s *AppointmentsService) RateClient(ctx context.Context, cr *models.ClientRating) error {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	if err := ensureMaster(ctx, s.r, cr.MasterId); err != nil {
+		return err
+	}
+	if err := cr.Validate(); err != nil {
+		return ValidationError{Msg: err.Error()}
+	}
+
+	apt, err := s.r.Appointments.GetById(ctx, cr.AppointmentId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoAppointments) {
+			return ErrAppointmentNotFound
+		}
+		l.Error("cannot get appointment", zap.Int64("appointment_id", cr.AppointmentId), zap.Error(err))
+		return ErrInternal
+	}
+	if apt.MasterID != cr.MasterId {
+		return ErrNotAppointmentMaster
+	}
+	if apt.Status != models.StatusCompleted {
+		return ErrClientNotRateable
+	}
+	cr.ClientId = apt.UserID
+
+	if err := s.r.ClientRatings.Upsert(ctx, cr); err != nil {
+		l.Error("failed to save client rating", zap.Int64("appointment_id", cr.AppointmentId), zap.Error(err))
+		return ErrInternal
+	}
+	l.Info("client rated", zap.Int64("client_id", cr.ClientId), zap.Int64("master_id", cr.MasterId))
+	return nil
+}
+
+// ClientReliability shows a master the ratings other masters gave the
+// client together with the reliability indicator. Masters only see clients
+// who booked with them.
+func (s *AppointmentsService) ClientReliability(ctx context.Context, masterId int64, clientId int64) (*models.ClientReliability, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	if err := ensureMaster(ctx, s.r, masterId); err != nil {
+		return nil, err
+	}
+	booked, err := s.r.Appointments.HasBooked(ctx, clientId, masterId)
+	if err != nil {
+		l.Error("failed to check client bookings", zap.Int64("client_id", clientId), zap.Error(err))
+		return nil, ErrInternal
+	}
+	if !booked {
+		return nil, ErrNotYourClient
+	}
+
+	ratings, err := s.r.ClientRatings.GetByClient(ctx, clientId)
+	if err != nil {
+		l.Error("failed to get client ratings", zap.Int64("client_id", clientId), zap.Error(err))
+		return nil, ErrInternal
+	}
+	stats, err := s.r.Appointments.GetClientStats(ctx, clientId)
+	if err != nil {
+		l.Error("failed to get client stats", zap.Int64("client_id", clientId), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	rel := &models.ClientReliability{ClientID: clientId, Stats: *stats, Ratings: ratings}
+	rel.Compute()
+	return rel, nil
+}
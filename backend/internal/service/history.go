@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"strawberry/internal/models"
+	"strawberry/pkg/cursor"
+	"strawberry/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// History pages through past and upcoming appointments of a client or a
+// master in any status.
+func (s *AppointmentsService) History(ctx context.Context, f models.HistoryFilter) (*models.HistoryPage, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	if err := f.Normalize(); err != nil {
+		return nil, ValidationError{Msg: err.Error()}
+	}
+
+	var after *models.HistoryCursor
+	if f.Cursor != "" {
+		after = &models.HistoryCursor{}
+		if err := cursor.Decode(f.Cursor, after); err != nil {
+			return nil, ValidationError{Msg: err.Error()}
+		}
+		if after.Sort != f.Sort {
+			return nil, ValidationError{Msg: "cursor belongs to another sort order"}
+		}
+	}
+
+	limit := f.Limit
+	f.Limit++
+	apts, err := s.r.Appointments.History(ctx, f, after)
+	if err != nil {
+		l.Error("failed to get appointment history", zap.Int64("user_id", f.UserID), zap.String("role", f.Role), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	page := &models.HistoryPage{Appointments: apts}
+	if len(apts) > limit {
+		page.Appointments = apts[:limit]
+		last := page.Appointments[limit-1]
+		page.NextCursor, err = cursor.Encode(models.HistoryCursor{Sort: f.Sort, ScheduledAt: last.ScheduledAt, ID: last.ID})
+		if err != nil {
+			l.Error("failed to encode history cursor", zap.Error(err))
+			return nil, ErrInternal
+		}
+	}
+	if page.Appointments == nil {
+		page.Appointments = []models.Appointment{}
+	}
+	return page, nil
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"strawberry/internal/models"
+	"strawberry/internal/repository"
+	"strawberry/pkg/cursor"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory_CursorOfAnotherSort(t *testing.T) {
+	s := &AppointmentsService{r: &repository.Repository{}}
+
+	next, err := cursor.Encode(models.HistoryCursor{Sort: models.SortDesc, ScheduledAt: time.Now(), ID: 7})
+	require.NoError(t, err)
+
+	_, err = s.History(context.Background(), models.HistoryFilter{
+		Role:   models.HistoryRoleClient,
+		UserID: 1,
+		Sort:   models.SortAsc,
+		Cursor: next,
+	})
+
+	var valErr ValidationError
+	require.ErrorAs(t, err, &valErr)
+}
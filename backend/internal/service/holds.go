@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strawberry/internal/models"
+	"strawberry/internal/repository"
+	"strawberry/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var ErrHoldNotFound = errors.New("slot hold not found")
+
+// HoldSlot reserves an available slot for the client for holdTTL, so other clients
+// can't book it meanwhile. A client holds at most one slot: the previous hold is released.
+func (s *AppointmentsService) HoldSlot(ctx context.Context, h *models.SlotHold) error {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	if h.UserID == h.MasterID {
+		return ValidationError{Msg: "can't book yourself"}
+	}
+	if !h.Time.After(time.Now()) {
+		return ValidationError{Msg: "slot is in the past"}
+	}
+
+	err := s.r.Appointments.CheckAvailability(ctx, h.MasterID, h.Time)
+	switch {
+	case err == nil:
+	case errors.Is(err, repository.ErrMasterUnavailable):
+		return ErrMasterUnavaliable
+	case errors.Is(err, repository.ErrAppointmentConflict):
+		return ErrAppointmentConflict
+	default:
+		l.Error("failed to check availability", zap.Error(err))
+		return ErrInternal
+	}
+
+	prev, err := s.r.SlotHolds.GetByUser(ctx, h.UserID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		l.Error("failed to get user's hold", zap.Int64("user_id", h.UserID), zap.Error(err))
+		return ErrInternal
+	}
+
+	ok, err := s.r.SlotHolds.Hold(ctx, h, s.holdTTL)
+	if err != nil {
+		l.Error("failed to hold slot", zap.Int64("master_id", h.MasterID), zap.Time("time", h.Time), zap.Error(err))
+		return ErrInternal
+	}
+	if !ok {
+		return ErrSlotHeld
+	}
+
+	if prev != nil && (prev.MasterID != h.MasterID || !prev.Time.Equal(h.Time)) {
+		s.releaseHold(ctx, prev.MasterID, prev.Time, h.UserID)
+	}
+
+	l.Info("slot held", zap.Int64("master_id", h.MasterID), zap.Int64("user_id", h.UserID), zap.Time("time", h.Time))
+	return nil
+}
+
+func (s *AppointmentsService) ReleaseSlot(ctx context.Context, userId int64, masterId int64, at time.Time) error {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	hold, err := s.r.SlotHolds.Get(ctx, masterId, at)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrHoldNotFound
+		}
+		l.Error("failed to get slot hold", zap.Error(err))
+		return ErrInternal
+	}
+	if hold.UserID != userId {
+		return ErrHoldNotFound
+	}
+
+	if err := s.r.SlotHolds.Release(ctx, masterId, at, userId); err != nil {
+		l.Error("failed to release slot", zap.Error(err))
+		return ErrInternal
+	}
+	return nil
+}
+
+// heldByOther reports whether someone other than userId holds the slot.
+func (s *AppointmentsService) heldByOther(ctx context.Context, masterId int64, at time.Time, userId int64) (bool, error) {
+	l := logger.FromContext(ctx)
+
+	hold, err := s.r.SlotHolds.Get(ctx, masterId, at)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return false, nil
+		}
+		l.Error("failed to get slot hold", zap.Int64("master_id", masterId), zap.Time("time", at), zap.Error(err))
+		return false, ErrInternal
+	}
+	return hold.UserID != userId, nil
+}
+
+// releaseHold converts the client's hold into the booked appointment. Failures
+// are only logged: the hold expires on its own.
+func (s *AppointmentsService) releaseHold(ctx context.Context, masterId int64, at time.Time, userId int64) {
+	if err := s.r.SlotHolds.Release(ctx, masterId, at, userId); err != nil {
+		logger.FromContext(ctx).Warn("failed to release slot hold", zap.Int64("master_id", masterId), zap.Time("time", at), zap.Error(err))
+	}
+}
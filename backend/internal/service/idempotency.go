@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strawberry/internal/models"
+	"strawberry/internal/repository"
+	"strawberry/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const defaultIdempotencyTTL = 24 * time.Hour
+
+var (
+	ErrIdempotencyMismatch   = errors.New("idempotency key was used with a different request")
+	ErrIdempotencyInProgress = errors.New("request with this idempotency key is in progress")
+)
+
+type IdempotencyService struct {
+	repo *repository.Repository
+	ttl  time.Duration
+}
+
+func newIdempotencyService(repo *repository.Repository, ttl time.Duration) Idempotency {
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+	return &IdempotencyService{repo: repo, ttl: ttl}
+}
+
+// Begin claims the key for a request with the given fingerprint. It returns the
+// stored response if the same request already completed, nil if the caller
+// should process the request, ErrIdempotencyMismatch if the key was used for
+// another request and ErrIdempotencyInProgress if the first request is still running.
+func (s *IdempotencyService) Begin(ctx context.Context, userId int64, key, fingerprint string) (*models.IdempotencyRecord, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	ok, err := s.repo.Idempotency.Reserve(ctx, userId, key, &models.IdempotencyRecord{Fingerprint: fingerprint}, s.ttl)
+	if err != nil {
+		l.Error("failed to reserve idempotency key", zap.Int64("user_id", userId), zap.Error(err))
+		return nil, ErrInternal
+	}
+	if ok {
+		return nil, nil
+	}
+
+	rec, err := s.repo.Idempotency.Get(ctx, userId, key)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			// expired between the calls
+			return s.Begin(ctx, userId, key, fingerprint)
+		}
+		l.Error("failed to get idempotency record", zap.Int64("user_id", userId), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	switch {
+	case rec.Fingerprint != fingerprint:
+		return nil, ErrIdempotencyMismatch
+	case !rec.Completed:
+		return nil, ErrIdempotencyInProgress
+	}
+	l.Info("replaying idempotent response", zap.Int64("user_id", userId), zap.Int("status", rec.Status))
+	return rec, nil
+}
+
+// Complete stores the response to replay it for retries with the same key.
+func (s *IdempotencyService) Complete(ctx context.Context, userId int64, key string, rec *models.IdempotencyRecord) error {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	rec.Completed = true
+	if err := s.repo.Idempotency.Save(ctx, userId, key, rec, s.ttl); err != nil {
+		l.Error("failed to save idempotency record", zap.Int64("user_id", userId), zap.Error(err))
+		return ErrInternal
+	}
+	return nil
+}
+
+// Abort frees the key so the request can be retried, e.g. after a server error.
+func (s *IdempotencyService) Abort(ctx context.Context, userId int64, key string) error {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	if err := s.repo.Idempotency.Delete(ctx, userId, key); err != nil {
+		l.Error("failed to delete idempotency record", zap.Int64("user_id", userId), zap.Error(err))
+		return ErrInternal
+	}
+	return nil
+}
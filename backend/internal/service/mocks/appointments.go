@@ -42,3 +42,81 @@ func (m *Appointments) GetByStatus(ctx context.Context, status string) ([]models
 	args := m.Called(ctx, status)
 	return args.Get(0).([]models.Appointment), args.Error(1)
 }
+
+func (m *Appointments) UpdateStatus(ctx context.Context, id int64, masterId int64, status string) error {
+	args := m.Called(ctx, id, masterId, status)
+	return args.Error(0)
+}
+
+func (m *Appointments) GetPolicy(ctx context.Context, masterId int64) (*models.CancellationPolicy, error) {
+	args := m.Called(ctx, masterId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CancellationPolicy), args.Error(1)
+}
+
+func (m *Appointments) SetPolicy(ctx context.Context, p *models.CancellationPolicy) error {
+	args := m.Called(ctx, p)
+	return args.Error(0)
+}
+
+func (m *Appointments) GetClientStats(ctx context.Context, masterId int64, clientId int64) (*models.ClientStats, error) {
+	args := m.Called(ctx, masterId, clientId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ClientStats), args.Error(1)
+}
+
+func (m *Appointments) RateClient(ctx context.Context, cr *models.ClientRating) error {
+	args := m.Called(ctx, cr)
+	return args.Error(0)
+}
+
+func (m *Appointments) ClientReliability(ctx context.Context, masterId int64, clientId int64) (*models.ClientReliability, error) {
+	args := m.Called(ctx, masterId, clientId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ClientReliability), args.Error(1)
+}
+
+func (m *Appointments) CreateRecurring(ctx context.Context, a *models.Appointment, rule *models.Recurrence) (*models.AppointmentSeries, []int64, error) {
+	args := m.Called(ctx, a, rule)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*models.AppointmentSeries), args.Get(1).([]int64), args.Error(2)
+}
+
+func (m *Appointments) CancelSeries(ctx context.Context, seriesId int64, userId int64) error {
+	args := m.Called(ctx, seriesId, userId)
+	return args.Error(0)
+}
+
+func (m *Appointments) HoldSlot(ctx context.Context, h *models.SlotHold) error {
+	args := m.Called(ctx, h)
+	return args.Error(0)
+}
+
+func (m *Appointments) ReleaseSlot(ctx context.Context, userId int64, masterId int64, at time.Time) error {
+	args := m.Called(ctx, userId, masterId, at)
+	return args.Error(0)
+}
+
+func (m *Appointments) SuggestAlternatives(ctx context.Context, userId int64, masterId int64, at time.Time, includeOthers bool) ([]models.SlotAlternative, error) {
+	args := m.Called(ctx, userId, masterId, at, includeOthers)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SlotAlternative), args.Error(1)
+}
+
+func (m *Appointments) History(ctx context.Context, f models.HistoryFilter) (*models.HistoryPage, error) {
+	args := m.Called(ctx, f)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HistoryPage), args.Error(1)
+}
@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"context"
+
+	"strawberry/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type Idempotency struct {
+	mock.Mock
+}
+
+func (m *Idempotency) Begin(ctx context.Context, userId int64, key, fingerprint string) (*models.IdempotencyRecord, error) {
+	args := m.Called(ctx, userId, key, fingerprint)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IdempotencyRecord), args.Error(1)
+}
+
+func (m *Idempotency) Complete(ctx context.Context, userId int64, key string, rec *models.IdempotencyRecord) error {
+	args := m.Called(ctx, userId, key, rec)
+	return args.Error(0)
+}
+
+func (m *Idempotency) Abort(ctx context.Context, userId int64, key string) error {
+	args := m.Called(ctx, userId, key)
+	return args.Error(0)
+}
@@ -0,0 +1,117 @@
+package mocks
+
+import (
+	"context"
+	"io"
+
+	"strawberry/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type Reviews struct {
+	mock.Mock
+}
+
+func (m *Reviews) Create(ctx context.Context, r *models.Review) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+func (m *Reviews) Update(ctx context.Context, r *models.Review) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+func (m *Reviews) GetByMasterId(ctx context.Context, f models.ReviewFilter) (*models.ReviewPage, error) {
+	args := m.Called(ctx, f)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReviewPage), args.Error(1)
+}
+
+func (m *Reviews) Revisions(ctx context.Context, reviewId int64) ([]models.ReviewRevision, error) {
+	args := m.Called(ctx, reviewId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ReviewRevision), args.Error(1)
+}
+
+func (m *Reviews) Delete(ctx context.Context, userId, id int64) error {
+	args := m.Called(ctx, userId, id)
+	return args.Error(0)
+}
+
+func (m *Reviews) AwaitingReview(ctx context.Context, userId int64) ([]models.Appointment, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Appointment), args.Error(1)
+}
+
+func (m *Reviews) CreateReply(ctx context.Context, reply *models.ReviewReply) error {
+	args := m.Called(ctx, reply)
+	return args.Error(0)
+}
+
+func (m *Reviews) UpdateReply(ctx context.Context, reply *models.ReviewReply) error {
+	args := m.Called(ctx, reply)
+	return args.Error(0)
+}
+
+func (m *Reviews) DeleteReply(ctx context.Context, masterId int64, reviewId int64) error {
+	args := m.Called(ctx, masterId, reviewId)
+	return args.Error(0)
+}
+
+func (m *Reviews) AddPhoto(ctx context.Context, userId int64, reviewId int64, data io.Reader, size int64) (*models.ReviewPhoto, error) {
+	args := m.Called(ctx, userId, reviewId, data, size)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReviewPhoto), args.Error(1)
+}
+
+func (m *Reviews) GetPhoto(ctx context.Context, reviewId int64, photoId int64) (io.ReadCloser, *models.ReviewPhoto, error) {
+	args := m.Called(ctx, reviewId, photoId)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(io.ReadCloser), args.Get(1).(*models.ReviewPhoto), args.Error(2)
+}
+
+func (m *Reviews) DeletePhoto(ctx context.Context, userId int64, reviewId int64, photoId int64) error {
+	args := m.Called(ctx, userId, reviewId, photoId)
+	return args.Error(0)
+}
+
+func (m *Reviews) Report(ctx context.Context, rep *models.ReviewReport) error {
+	args := m.Called(ctx, rep)
+	return args.Error(0)
+}
+
+func (m *Reviews) ModerationQueue(ctx context.Context, moderatorId int64, status string) ([]models.ModerationCase, error) {
+	args := m.Called(ctx, moderatorId, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ModerationCase), args.Error(1)
+}
+
+func (m *Reviews) Resolve(ctx context.Context, moderatorId int64, reviewId int64, decision string) error {
+	args := m.Called(ctx, moderatorId, reviewId, decision)
+	return args.Error(0)
+}
+
+func (m *Reviews) Vote(ctx context.Context, v *models.ReviewVote) error {
+	args := m.Called(ctx, v)
+	return args.Error(0)
+}
+
+func (m *Reviews) DeleteVote(ctx context.Context, userId int64, reviewId int64) error {
+	args := m.Called(ctx, userId, reviewId)
+	return args.Error(0)
+}
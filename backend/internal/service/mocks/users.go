@@ -50,19 +50,28 @@ func (m *Users) GetByUsername(ctx context.Context, username string) (*models.Use
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
-func (m *Users) GetMastersByRating(ctx context.Context) ([]models.User, error) {
-	args := m.Called(ctx)
-	return args.Get(0).([]models.User), args.Error(1)
+func (m *Users) FindMasters(ctx context.Context, f models.MasterFilter) (*models.MasterPage, error) {
+	args := m.Called(ctx, f)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MasterPage), args.Error(1)
 }
 
-func (m *Users) GetMastersBySpecialization(ctx context.Context, spec string) ([]models.User, error) {
-	args := m.Called(ctx, spec)
-	return args.Get(0).([]models.User), args.Error(1)
+func (m *Users) Suggest(ctx context.Context, q models.SuggestQuery) ([]models.Suggestion, error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Suggestion), args.Error(1)
 }
 
-func (m *Users) Search(ctx context.Context, query string) ([]models.User, error) {
-	args := m.Called(ctx, query)
-	return args.Get(0).([]models.User), args.Error(1)
+func (m *Users) Search(ctx context.Context, q models.SearchQuery) (*models.SearchPage, error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SearchPage), args.Error(1)
 }
 
 func (m *Users) ChangePassword(ctx context.Context, email string, new_pswrd string) error {
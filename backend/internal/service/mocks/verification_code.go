@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type VerificationCode struct {
+	mock.Mock
+}
+
+func (m *VerificationCode) SendCode(ctx context.Context, email string) (string, error) {
+	args := m.Called(ctx, email)
+	return args.String(0), args.Error(1)
+}
+
+func (m *VerificationCode) VerifyCode(ctx context.Context, email, inputCode string) error {
+	args := m.Called(ctx, email, inputCode)
+	return args.Error(0)
+}
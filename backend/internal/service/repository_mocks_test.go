@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"strawberry/internal/models"
+	"strawberry/internal/repository"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// Repository fakes for service tests. Each embeds the repository interface
+// and implements only the methods the tests call.
+
+type schedulesRepoMock struct {
+	repository.Schedules
+	mock.Mock
+}
+
+func (m *schedulesRepoMock) GetDaysOff(ctx context.Context, userId int64) ([]time.Time, error) {
+	args := m.Called(ctx, userId)
+	return args.Get(0).([]time.Time), args.Error(1)
+}
+
+func (m *schedulesRepoMock) GetSlotsByDay(ctx context.Context, userId int64, date time.Time, dayOfWeek string) ([]time.Time, error) {
+	args := m.Called(ctx, userId, date, dayOfWeek)
+	return args.Get(0).([]time.Time), args.Error(1)
+}
+
+func (m *schedulesRepoMock) SetWorkingSlotsByWeekDay(ctx context.Context, userId int64, dayOfWeek string, slots []string) error {
+	return m.Called(ctx, userId, dayOfWeek, slots).Error(0)
+}
+
+type appointmentsRepoMock struct {
+	repository.Appointments
+	mock.Mock
+}
+
+func (m *appointmentsRepoMock) GetByDate(ctx context.Context, id int64, date time.Time) ([]models.Appointment, error) {
+	args := m.Called(ctx, id, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Appointment), args.Error(1)
+}
+
+func (m *appointmentsRepoMock) HasBooked(ctx context.Context, clientId, masterId int64) (bool, error) {
+	args := m.Called(ctx, clientId, masterId)
+	return args.Bool(0), args.Error(1)
+}
+
+type slotHoldsRepoMock struct {
+	repository.SlotHolds
+	mock.Mock
+}
+
+func (m *slotHoldsRepoMock) GetHeldSlots(ctx context.Context, masterId int64, date time.Time) ([]string, error) {
+	args := m.Called(ctx, masterId, date)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+type timeBlocksRepoMock struct {
+	repository.TimeBlocks
+	mock.Mock
+}
+
+func (m *timeBlocksRepoMock) GetByRange(ctx context.Context, masterId int64, from, to time.Time) ([]models.TimeBlock, error) {
+	args := m.Called(ctx, masterId, from, to)
+	return args.Get(0).([]models.TimeBlock), args.Error(1)
+}
+
+type policiesRepoMock struct {
+	repository.CancellationPolicies
+	mock.Mock
+}
+
+func (m *policiesRepoMock) Get(ctx context.Context, masterId int64) (*models.CancellationPolicy, error) {
+	args := m.Called(ctx, masterId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CancellationPolicy), args.Error(1)
+}
+
+type usersRepoMock struct {
+	repository.Users
+	mock.Mock
+}
+
+func (m *usersRepoMock) GetById(ctx context.Context, id int64) (*models.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+type reviewsRepoMock struct {
+	repository.Reviews
+	mock.Mock
+}
+
+func (m *reviewsRepoMock) GetById(ctx context.Context, id int64) (*models.Review, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Review), args.Error(1)
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strawberry/internal/models"
+	"strawberry/internal/repository"
+	"strawberry/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrPhotoNotFound = errors.New("photo not found")
+	ErrTooManyPhotos = fmt.Errorf("a review can have at most %d photos", models.MaxReviewPhotos)
+)
+
+// AddPhoto attaches an image to the user's own review. The type is sniffed
+// from the content, the client supplied header is not trusted.
+func (s *ReviewsService) AddPhoto(ctx context.Context, userId int64, reviewId int64, data io.Reader, size int64) (*models.ReviewPhoto, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	if size <= 0 || size > models.MaxReviewPhotoSize {
+		return nil, ValidationError{Msg: fmt.Sprintf("photo must be at most %d MB", models.MaxReviewPhotoSize>>20)}
+	}
+	head := make([]byte, 512)
+	n, err := io.ReadFull(data, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, ValidationError{Msg: "can't read photo"}
+	}
+	contentType := http.DetectContentType(head[:n])
+	if !models.ReviewPhotoTypes[contentType] {
+		return nil, ValidationError{Msg: "photo must be a JPEG, PNG or WebP image"}
+	}
+
+	rev, err := s.repo.Reviews.GetById(ctx, reviewId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		l.Error("failed to get review", zap.Int64("review_id", reviewId), zap.Error(err))
+		return nil, ErrInternal
+	}
+	if rev.UserId != userId {
+		return nil, ErrUnauthorized
+	}
+
+	photo := &models.ReviewPhoto{ReviewId: reviewId, ContentType: contentType, Size: size}
+	if err := s.repo.Reviews.AddPhoto(ctx, photo, models.MaxReviewPhotos); err != nil {
+		if errors.Is(err, repository.ErrPhotoLimit) {
+			return nil, ErrTooManyPhotos
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		l.Error("failed to save review photo", zap.Int64("review_id", reviewId), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	body := io.MultiReader(bytes.NewReader(head[:n]), data)
+	if err := s.minio.UploadReviewPhoto(reviewId, photo.Id, body, size, contentType); err != nil {
+		l.Error("failed to upload review photo", zap.Int64("review_id", reviewId), zap.Error(err))
+		if err := s.repo.Reviews.DeletePhoto(ctx, reviewId, photo.Id); err != nil {
+			l.Error("failed to roll back review photo", zap.Int64("photo_id", photo.Id), zap.Error(err))
+		}
+		return nil, ErrInternal
+	}
+
+	l.Info("review photo added", zap.Int64("review_id", reviewId), zap.Int64("photo_id", photo.Id))
+	return photo, nil
+}
+
+func (s *ReviewsService) GetPhoto(ctx context.Context, reviewId int64, photoId int64) (io.ReadCloser, *models.ReviewPhoto, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	rev, err := s.repo.Reviews.GetById(ctx, reviewId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, ErrPhotoNotFound
+		}
+		l.Error("failed to get review", zap.Int64("review_id", reviewId), zap.Error(err))
+		return nil, nil, ErrInternal
+	}
+	// Photos of hidden and removed reviews are no longer public.
+	if rev.Moderation != models.ReviewVisible {
+		return nil, nil, ErrPhotoNotFound
+	}
+
+	photo, err := s.repo.Reviews.GetPhoto(ctx, reviewId, photoId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, ErrPhotoNotFound
+		}
+		l.Error("failed to get review photo", zap.Int64("photo_id", photoId), zap.Error(err))
+		return nil, nil, ErrInternal
+	}
+
+	rc, err := s.minio.GetReviewPhoto(reviewId, photoId)
+	if err != nil {
+		l.Error("failed to download review photo", zap.Int64("photo_id", photoId), zap.Error(err))
+		return nil, nil, ErrInternal
+	}
+	return rc, photo, nil
+}
+
+func (s *ReviewsService) DeletePhoto(ctx context.Context, userId int64, reviewId int64, photoId int64) error {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	rev, err := s.repo.Reviews.GetById(ctx, reviewId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrReviewNotFound
+		}
+		l.Error("failed to get review", zap.Int64("review_id", reviewId), zap.Error(err))
+		return ErrInternal
+	}
+	if rev.UserId != userId {
+		return ErrUnauthorized
+	}
+
+	if err := s.repo.Reviews.DeletePhoto(ctx, reviewId, photoId); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrPhotoNotFound
+		}
+		l.Error("failed to delete review photo", zap.Int64("photo_id", photoId), zap.Error(err))
+		return ErrInternal
+	}
+	s.removePhotoObjects(ctx, []models.ReviewPhoto{{Id: photoId, ReviewId: reviewId}})
+	return nil
+}
+
+// removePhotoObjects deletes stored images whose metadata is already gone.
+// Failures only leave orphaned objects behind, so they are logged and skipped.
+func (s *ReviewsService) removePhotoObjects(ctx context.Context, photos []models.ReviewPhoto) {
+	l := logger.FromContext(ctx)
+	for _, p := range photos {
+		if err := s.minio.DeleteReviewPhoto(p.ReviewId, p.Id); err != nil {
+			l.Warn("failed to remove review photo object", zap.Int64("review_id", p.ReviewId), zap.Int64("photo_id", p.Id), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+	"strawberry/internal/models"
+	"strawberry/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPhoto_RemovedReview(t *testing.T) {
+	reviews := &reviewsRepoMock{}
+	reviews.On("GetById", mock.Anything, int64(3)).
+		Return(&models.Review{Id: 3, Moderation: models.ReviewRemoved}, nil)
+
+	s := &ReviewsService{repo: &repository.Repository{Reviews: reviews}}
+
+	_, _, err := s.GetPhoto(context.Background(), 3, 7)
+
+	require.ErrorIs(t, err, ErrPhotoNotFound)
+	reviews.AssertExpectations(t)
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strawberry/internal/models"
+	"strawberry/internal/repository"
+	"strawberry/pkg/helper"
+	"strawberry/pkg/logger"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrReviewNotFound    = errors.New("review not found")
+	ErrReplyNotFound     = errors.New("reply not found")
+	ErrNotReviewedMaster = errors.New("only the reviewed master can reply")
+	ErrAlreadyReplied    = errors.New("review already has a reply")
+)
+
+// CreateReply adds the reviewed master's answer to a review and notifies the reviewer.
+func (s *ReviewsService) CreateReply(ctx context.Context, reply *models.ReviewReply) error {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	if err := reply.Validate(); err != nil {
+		return ValidationError{Msg: err.Error()}
+	}
+	rev, err := s.reviewOfMaster(ctx, reply.ReviewId, reply.MasterId)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Reviews.CreateReply(ctx, reply); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return ErrAlreadyReplied
+		}
+		l.Error("failed to create review reply", zap.Int64("review_id", reply.ReviewId), zap.Error(err))
+		return ErrInternal
+	}
+
+	go func(rev *models.Review, reply models.ReviewReply) {
+		bgCtx := logger.WithLogger(context.Background())
+		if err := s.publishReviewReplied(bgCtx, rev, &reply); err != nil {
+			logger.FromContext(bgCtx).Error("cannot publish review reply to rmq (async)", zap.Error(err))
+		}
+	}(rev, *reply)
+
+	l.Info("review reply created", zap.Int64("review_id", reply.ReviewId))
+	return nil
+}
+
+func (s *ReviewsService) UpdateReply(ctx context.Context, reply *models.ReviewReply) error {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	if err := reply.Validate(); err != nil {
+		return ValidationError{Msg: err.Error()}
+	}
+	if _, err := s.reviewOfMaster(ctx, reply.ReviewId, reply.MasterId); err != nil {
+		return err
+	}
+
+	if err := s.repo.Reviews.UpdateReply(ctx, reply); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrReplyNotFound
+		}
+		l.Error("failed to update review reply", zap.Int64("review_id", reply.ReviewId), zap.Error(err))
+		return ErrInternal
+	}
+	return nil
+}
+
+func (s *ReviewsService) DeleteReply(ctx context.Context, masterId int64, reviewId int64) error {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	if _, err := s.reviewOfMaster(ctx, reviewId, masterId); err != nil {
+		return err
+	}
+
+	if err := s.repo.Reviews.DeleteReply(ctx, reviewId); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrReplyNotFound
+		}
+		l.Error("failed to delete review reply", zap.Int64("review_id", reviewId), zap.Error(err))
+		return ErrInternal
+	}
+	return nil
+}
+
+// reviewOfMaster loads the review and makes sure it was left for masterId.
+func (s *ReviewsService) reviewOfMaster(ctx context.Context, reviewId int64, masterId int64) (*models.Review, error) {
+	rev, err := s.repo.Reviews.GetById(ctx, reviewId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		logger.FromContext(ctx).Error("failed to get review", zap.Int64("review_id", reviewId), zap.Error(err))
+		return nil, ErrInternal
+	}
+	if rev.MasterId != masterId {
+		return nil, ErrNotReviewedMaster
+	}
+	return rev, nil
+}
+
+// publishReviewReplied notifies the reviewer, so the payload carries user_id only.
+func (s *ReviewsService) publishReviewReplied(ctx context.Context, rev *models.Review, reply *models.ReviewReply) error {
+	l := logger.FromContext(ctx)
+
+	notification := struct {
+		ReviewId int64     `json:"review_id"`
+		UserId   int64     `json:"user_id"`
+		Rating   int       `json:"rating"`
+		Reply    string    `json:"reply"`
+		Time     time.Time `json:"created_at"`
+	}{
+		ReviewId: rev.Id,
+		UserId:   rev.UserId,
+		Rating:   rev.Rating,
+		Reply:    reply.Text,
+		Time:     reply.CreatedAt,
+	}
+
+	return helper.Retry(ctx, 3, 100*time.Millisecond, func() error {
+		body, err := json.Marshal(notification)
+		if err != nil {
+			l.Error("failed to marshal reviews.replied payload", zap.Error(err))
+			return err
+		}
+		if err := s.rmq.Channel.Publish(
+			"reviews",
+			"reviews.replied",
+			false,
+			false,
+			amqp091.Publishing{
+				ContentType: "application/json",
+				Body:        body,
+			},
+		); err != nil {
+			l.Error("can't send message to rmq", zap.String("reason", err.Error()))
+			return ErrInternal
+		}
+		return nil
+	})
+}
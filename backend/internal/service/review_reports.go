@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strawberry/internal/models"
+	"strawberry/internal/repository"
+	"strawberry/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrNotModerator     = errors.New("only moderators can do this")
+	ErrAlreadyReported  = errors.New("review is already reported by this user")
+	ErrOwnReview        = errors.New("can't report own review")
+	ErrNothingToResolve = errors.New("review has no open reports")
+)
+
+// Report flags a review. Once enough distinct users report it, the review
+// is hidden from listings and ratings until a moderator decides.
+func (s *ReviewsService) Report(ctx context.Context, rep *models.ReviewReport) error {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	if err := rep.Validate(); err != nil {
+		return ValidationError{Msg: err.Error()}
+	}
+
+	rev, err := s.repo.Reviews.GetById(ctx, rep.ReviewId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrReviewNotFound
+		}
+		l.Error("failed to get review", zap.Int64("review_id", rep.ReviewId), zap.Error(err))
+		return ErrInternal
+	}
+	if rev.Moderation == models.ReviewRemoved {
+		return ErrReviewNotFound
+	}
+	if rev.UserId == rep.ReporterId {
+		return ErrOwnReview
+	}
+
+	hidden, err := s.repo.Reviews.Report(ctx, rep, s.hideThreshold)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrConflict):
+			return ErrAlreadyReported
+		case errors.Is(err, repository.ErrNotFound):
+			return ErrReviewNotFound
+		}
+		l.Error("failed to report review", zap.Int64("review_id", rep.ReviewId), zap.Error(err))
+		return ErrInternal
+	}
+
+	l.Info("review reported", zap.Int64("review_id", rep.ReviewId), zap.String("reason", rep.Reason), zap.Bool("hidden", hidden))
+	return nil
+}
+
+func (s *ReviewsService) ModerationQueue(ctx context.Context, moderatorId int64, status string) ([]models.ModerationCase, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	if !s.moderators[moderatorId] {
+		return nil, ErrNotModerator
+	}
+	if status == "" {
+		status = models.ReportOpen
+	}
+	if status != models.ReportOpen && status != models.ReportDismissed && status != models.ReportRemoved {
+		return nil, ValidationError{Msg: "status must be open, dismissed or removed"}
+	}
+
+	cases, err := s.repo.Reviews.ModerationQueue(ctx, status)
+	if err != nil {
+		l.Error("failed to get moderation queue", zap.String("status", status), zap.Error(err))
+		return nil, ErrInternal
+	}
+	if cases == nil {
+		cases = []models.ModerationCase{}
+	}
+	return cases, nil
+}
+
+// Resolve closes the open reports of a review: dismissed brings a hidden
+// review back, removed hides it permanently.
+func (s *ReviewsService) Resolve(ctx context.Context, moderatorId int64, reviewId int64, decision string) error {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	if !s.moderators[moderatorId] {
+		return ErrNotModerator
+	}
+	if decision != models.ReportDismissed && decision != models.ReportRemoved {
+		return ValidationError{Msg: "decision must be dismissed or removed"}
+	}
+
+	if err := s.repo.Reviews.Resolve(ctx, reviewId, decision, moderatorId); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNothingToResolve
+		}
+		l.Error("failed to resolve review reports", zap.Int64("review_id", reviewId), zap.Error(err))
+		return ErrInternal
+	}
+
+	l.Info("review moderated", zap.Int64("review_id", reviewId), zap.String("decision", decision), zap.Int64("moderator_id", moderatorId))
+	return nil
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strawberry/internal/models"
+	"strawberry/internal/repository"
+	"strawberry/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrVoteOwnReview = errors.New("can't vote on own review")
+	ErrVoteNotFound  = errors.New("vote not found")
+)
+
+// Vote marks a public review as helpful or not for the user. Voting again
+// replaces the previous vote.
+func (s *ReviewsService) Vote(ctx context.Context, v *models.ReviewVote) error {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	rev, err := s.repo.Reviews.GetById(ctx, v.ReviewId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrReviewNotFound
+		}
+		l.Error("failed to get review", zap.Int64("review_id", v.ReviewId), zap.Error(err))
+		return ErrInternal
+	}
+	if rev.Moderation != models.ReviewVisible {
+		return ErrReviewNotFound
+	}
+	if rev.UserId == v.UserId {
+		return ErrVoteOwnReview
+	}
+
+	if err := s.repo.Reviews.Vote(ctx, v); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrReviewNotFound
+		}
+		l.Error("failed to vote on review", zap.Int64("review_id", v.ReviewId), zap.Error(err))
+		return ErrInternal
+	}
+	return nil
+}
+
+func (s *ReviewsService) DeleteVote(ctx context.Context, userId int64, reviewId int64) error {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	if err := s.repo.Reviews.DeleteVote(ctx, reviewId, userId); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrVoteNotFound
+		}
+		l.Error("failed to delete review vote", zap.Int64("review_id", reviewId), zap.Error(err))
+		return ErrInternal
+	}
+	return nil
+}
@@ -6,8 +6,10 @@ import (
 	"errors"
 	"strawberry/internal/models"
 	"strawberry/internal/repository"
+	"strawberry/pkg/cursor"
 	"strawberry/pkg/helper"
 	"strawberry/pkg/logger"
+	minio_client "strawberry/pkg/minio"
 	"strawberry/pkg/rabbitmq"
 	"time"
 
@@ -16,24 +18,68 @@ import (
 )
 
 type ReviewsService struct {
-	repo *repository.Repository
-	rmq  *rabbitmq.MQConnection
+	repo  *repository.Repository
+	rmq   *rabbitmq.MQConnection
+	minio *minio_client.MinioClient
+
+	moderators    map[int64]bool
+	hideThreshold int
+	screeners     ScreeningPipeline
 }
 
 var (
-	ErrNotFound           = errors.New("resource not found")
-	ErrConflict           = errors.New("resource already exists")
-	ErrInvalidReference   = errors.New("invalid foreign key reference")
-	ErrNoPastAppointments = errors.New("no past appointments with this master")
+	ErrNotFound                = errors.New("resource not found")
+	ErrConflict                = errors.New("resource already exists")
+	ErrInvalidReference        = errors.New("invalid foreign key reference")
+	ErrNotReviewable           = errors.New("appointment does not belong to the reviewer and master")
+	ErrAppointmentNotCompleted = errors.New("only completed appointments can be reviewed")
+	ErrAlreadyReviewed         = errors.New("appointment is already reviewed")
 )
 
-func newReviewsService(r *repository.Repository, rmq *rabbitmq.MQConnection) *ReviewsService {
+func newReviewsService(r *repository.Repository, rmq *rabbitmq.MQConnection, minio *minio_client.MinioClient, moderatorIds []int64, hideThreshold int, screening ScreeningConfig) *ReviewsService {
+	moderators := make(map[int64]bool, len(moderatorIds))
+	for _, id := range moderatorIds {
+		moderators[id] = true
+	}
+	if hideThreshold <= 0 {
+		hideThreshold = 3
+	}
 	return &ReviewsService{
-		repo: r,
-		rmq:  rmq,
+		repo:          r,
+		rmq:           rmq,
+		minio:         minio,
+		moderators:    moderators,
+		hideThreshold: hideThreshold,
+		screeners:     newScreeningPipeline(r, screening),
 	}
 }
 
+// screen runs the screening pipeline over a new or edited review and records
+// its findings on it. Rejected reviews get a ReviewRejectedError, suspicious
+// ones are marked hidden until a moderator looks at them.
+func (s *ReviewsService) screen(ctx context.Context, r *models.Review) error {
+	l := logger.FromContext(ctx)
+
+	res, err := s.screeners.Run(ctx, r)
+	if err != nil {
+		l.Error("review screening failed", zap.Int64("user_id", r.UserId), zap.Error(err))
+		return ErrInternal
+	}
+
+	r.Screening = res.Findings
+	r.Moderation = ""
+	switch res.Verdict {
+	case models.ScreeningReject:
+		reasons := res.Reasons(models.ScreeningReject)
+		l.Info("review rejected by screening", zap.Int64("user_id", r.UserId), zap.Strings("reasons", reasons))
+		return ReviewRejectedError{Reasons: reasons}
+	case models.ScreeningHide:
+		l.Info("review hidden by screening", zap.Int64("user_id", r.UserId), zap.Strings("reasons", res.Reasons(models.ScreeningHide)))
+		r.Moderation = models.ReviewHidden
+	}
+	return nil
+}
+
 func (s *ReviewsService) publishReviewCreated(ctx context.Context, r *models.Review) error {
 	l := logger.FromContext(ctx)
 
@@ -81,35 +127,46 @@ func (s *ReviewsService) Create(ctx context.Context, r *models.Review) error {
 
 	l.Info("start creating review", zap.Int64("user_id", r.UserId), zap.Int64("master_id", r.MasterId))
 
-	appointments, err := s.repo.Appointments.GetByUserId(ctx, r.UserId)
-	if err != nil {
-		l.Error("cannot get appointment", zap.Error(err))
-		return err
+	if r.AppointmentId == nil {
+		return ValidationError{Msg: "appointment_id is required"}
 	}
-
-	hasPastAppointment := false
-	now := time.Now()
-	for _, appointment := range appointments {
-		if appointment.ScheduledAt.Before(now) {
-			hasPastAppointment = true
-			break
+	apt, err := s.repo.Appointments.GetById(ctx, *r.AppointmentId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoAppointments) {
+			return ErrAppointmentNotFound
 		}
+		l.Error("cannot get appointment", zap.Int64("appointment_id", *r.AppointmentId), zap.Error(err))
+		return ErrInternal
+	}
+	if r.MasterId == 0 {
+		r.MasterId = apt.MasterID
+	}
+	if apt.UserID != r.UserId || apt.MasterID != r.MasterId {
+		l.Warn("review for someone else's appointment", zap.Int64("user_id", r.UserId), zap.Int64("appointment_id", *r.AppointmentId))
+		return ErrNotReviewable
+	}
+	if apt.Status != models.StatusCompleted {
+		return ErrAppointmentNotCompleted
 	}
 
-	if !hasPastAppointment {
-		l.Warn("user has no past appointments, cannot leave review", zap.Int64("user_id", r.UserId))
-		return ErrNoPastAppointments
+	if err := s.screen(ctx, r); err != nil {
+		return err
 	}
 
 	err = s.repo.Reviews.Create(ctx, r)
 	if err != nil {
-		l.Error("cannot create review", zap.Error(err))
 		if errors.Is(err, repository.ErrConflict) {
-			return ErrConflict
+			return ErrAlreadyReviewed
 		}
+		l.Error("cannot create review", zap.Error(err))
 		return err
 	}
 
+	l.Info("review created", zap.Int64("review_id", r.Id), zap.String("moderation", r.Moderation))
+	if r.Moderation != models.ReviewVisible {
+		return nil
+	}
+
 	go func(r *models.Review) {
 		bgCtx := context.Background()
 		bgCtx = logger.WithLogger(bgCtx)
@@ -120,11 +177,25 @@ func (s *ReviewsService) Create(ctx context.Context, r *models.Review) error {
 		}
 	}(r)
 
-	l.Info("review created", zap.Int64("review_id", r.Id))
-
 	return nil
 }
 
+// AwaitingReview lists the client's completed appointments without a review.
+func (s *ReviewsService) AwaitingReview(ctx context.Context, userId int64) ([]models.Appointment, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	apts, err := s.repo.Appointments.GetAwaitingReview(ctx, userId)
+	if err != nil {
+		l.Error("failed to get appointments awaiting review", zap.Int64("user_id", userId), zap.Error(err))
+		return nil, ErrInternal
+	}
+	if apts == nil {
+		apts = []models.Appointment{}
+	}
+	return apts, nil
+}
+
 func (s *ReviewsService) GetById(ctx context.Context, id int64) (*models.Review, error) {
 	ctx = logger.WithLogger(ctx)
 	l := logger.FromContext(ctx)
@@ -141,16 +212,54 @@ func (s *ReviewsService) GetById(ctx context.Context, id int64) (*models.Review,
 	return rev, nil
 }
 
-func (s *ReviewsService) GetByMasterId(ctx context.Context, masterId int64) ([]models.Review, error) {
+// GetByMasterId pages through the master's public reviews.
+func (s *ReviewsService) GetByMasterId(ctx context.Context, f models.ReviewFilter) (*models.ReviewPage, error) {
 	ctx = logger.WithLogger(ctx)
 	l := logger.FromContext(ctx)
 
-	reviews, err := s.repo.Reviews.GetByMasterId(ctx, masterId)
+	if err := f.Normalize(); err != nil {
+		return nil, ValidationError{Msg: err.Error()}
+	}
+
+	var after *models.ReviewCursor
+	if f.Cursor != "" {
+		after = &models.ReviewCursor{}
+		if err := cursor.Decode(f.Cursor, after); err != nil {
+			return nil, ValidationError{Msg: err.Error()}
+		}
+		if after.Sort != f.Sort {
+			return nil, ValidationError{Msg: "cursor belongs to another sort order"}
+		}
+	}
+
+	limit := f.Limit
+	f.Limit++
+	reviews, err := s.repo.Reviews.GetByMasterId(ctx, f, after)
 	if err != nil {
-		l.Error("failed to get reviews by master id", zap.Error(err))
-		return nil, err
+		l.Error("failed to get reviews by master id", zap.Int64("master_id", f.MasterID), zap.Error(err))
+		return nil, ErrInternal
 	}
-	return reviews, nil
+
+	page := &models.ReviewPage{Reviews: reviews}
+	if len(reviews) > limit {
+		page.Reviews = reviews[:limit]
+		last := page.Reviews[limit-1]
+		page.NextCursor, err = cursor.Encode(models.ReviewCursor{
+			Sort:      f.Sort,
+			Rating:    last.Rating,
+			Helpful:   last.HelpfulCount,
+			CreatedAt: last.CreatedAt,
+			ID:        last.Id,
+		})
+		if err != nil {
+			l.Error("failed to encode reviews cursor", zap.Error(err))
+			return nil, ErrInternal
+		}
+	}
+	if page.Reviews == nil {
+		page.Reviews = []models.Review{}
+	}
+	return page, nil
 }
 
 func (s *ReviewsService) Update(ctx context.Context, r *models.Review) error {
@@ -167,6 +276,10 @@ func (s *ReviewsService) Update(ctx context.Context, r *models.Review) error {
 		return ErrUnauthorized
 	}
 
+	if err := s.screen(ctx, r); err != nil {
+		return err
+	}
+
 	err = s.repo.Reviews.Update(ctx, r)
 	if err != nil {
 		if errors.Is(err, ErrNotFound) {
@@ -180,6 +293,31 @@ func (s *ReviewsService) Update(ctx context.Context, r *models.Review) error {
 	return nil
 }
 
+// Revisions lists earlier versions of a public review, oldest first.
+func (s *ReviewsService) Revisions(ctx context.Context, reviewId int64) ([]models.ReviewRevision, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	rev, err := s.repo.Reviews.GetById(ctx, reviewId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		l.Error("failed to get review", zap.Int64("review_id", reviewId), zap.Error(err))
+		return nil, ErrInternal
+	}
+	if rev.Moderation != models.ReviewVisible {
+		return nil, ErrReviewNotFound
+	}
+
+	revisions, err := s.repo.Reviews.GetRevisions(ctx, reviewId)
+	if err != nil {
+		l.Error("failed to get review revisions", zap.Int64("review_id", reviewId), zap.Error(err))
+		return nil, ErrInternal
+	}
+	return revisions, nil
+}
+
 func (s *ReviewsService) Delete(ctx context.Context, userId int64, id int64) error {
 	ctx = logger.WithLogger(ctx)
 	l := logger.FromContext(ctx)
@@ -194,6 +332,12 @@ func (s *ReviewsService) Delete(ctx context.Context, userId int64, id int64) err
 		return ErrUnauthorized
 	}
 
+	photos, err := s.repo.Reviews.GetPhotos(ctx, []int64{id})
+	if err != nil {
+		l.Error("failed to get review photos", zap.Int64("review_id", id), zap.Error(err))
+		return err
+	}
+
 	err = s.repo.Reviews.Delete(ctx, id)
 	if err != nil {
 		if errors.Is(err, ErrNotFound) {
@@ -203,6 +347,7 @@ func (s *ReviewsService) Delete(ctx context.Context, userId int64, id int64) err
 		l.Error("failed to delete review", zap.Error(err))
 		return err
 	}
+	s.removePhotoObjects(ctx, photos[id])
 	l.Info("review deleted", zap.Int64("review_id", id))
 	return nil
 }
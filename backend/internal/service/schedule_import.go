@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strawberry/internal/models"
+	"strawberry/internal/repository"
+	"strawberry/pkg/ical"
+	"strawberry/pkg/logger"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	importHistory      = 24 * time.Hour
+	importHorizon      = 365 * 24 * time.Hour
+	importFetchTimeout = 15 * time.Second
+)
+
+var (
+	ErrInvalidCalendar     = errors.New("invalid calendar file")
+	ErrInvalidCalendarURL  = errors.New("invalid calendar url")
+	ErrCalendarUnavailable = errors.New("calendar can't be fetched")
+	ErrNoCalendarURL       = errors.New("calendar url is not set")
+
+	errPrivateAddress = errors.New("calendar host resolves to a non-public address")
+)
+
+// ImportCalendar replaces the master's imported busy times with events of an uploaded .ics file.
+func (s *SchedulesService) ImportCalendar(ctx context.Context, masterId int64, data io.Reader) (*models.CalendarImport, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	if err := ensureMaster(ctx, s.repo, masterId); err != nil {
+		return nil, err
+	}
+
+	cal, err := ical.Decode(data, s.loc)
+	if err != nil {
+		l.Warn("failed to decode calendar", zap.Int64("master_id", masterId), zap.Error(err))
+		return nil, ErrInvalidCalendar
+	}
+	return s.replaceImported(ctx, masterId, cal)
+}
+
+// SetCalendarURL registers a published calendar of the master and imports it right away.
+func (s *SchedulesService) SetCalendarURL(ctx context.Context, masterId int64, rawURL string) (*models.CalendarImport, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	if err := ensureMaster(ctx, s.repo, masterId); err != nil {
+		return nil, err
+	}
+
+	calURL, err := normalizeCalendarURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if !s.allowPrivateURLs {
+		if err := checkPublicHost(ctx, calURL); err != nil {
+			l.Warn("calendar url rejected", zap.String("url", calURL), zap.Error(err))
+			return nil, ErrInvalidCalendarURL
+		}
+	}
+
+	cal, err := s.fetchCalendar(ctx, calURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.TimeBlocks.SetImportURL(ctx, masterId, calURL); err != nil {
+		l.Error("failed to save calendar url", zap.Int64("master_id", masterId), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	imp, err := s.replaceImported(ctx, masterId, cal)
+	if err != nil {
+		return nil, err
+	}
+	imp.URL = calURL
+	return imp, nil
+}
+
+// RefreshCalendar re-imports the master's registered calendar url.
+func (s *SchedulesService) RefreshCalendar(ctx context.Context, masterId int64) (*models.CalendarImport, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	stored, err := s.repo.TimeBlocks.GetImport(ctx, masterId)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		l.Error("failed to get calendar import", zap.Int64("master_id", masterId), zap.Error(err))
+		return nil, ErrInternal
+	}
+	if stored == nil || stored.URL == "" {
+		return nil, ErrNoCalendarURL
+	}
+
+	cal, err := s.fetchCalendar(ctx, stored.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	imp, err := s.replaceImported(ctx, masterId, cal)
+	if err != nil {
+		return nil, err
+	}
+	imp.URL = stored.URL
+	return imp, nil
+}
+
+func (s *SchedulesService) fetchCalendar(ctx context.Context, calURL string) (*ical.Calendar, error) {
+	l := logger.FromContext(ctx)
+
+	ctx, cancel := context.WithTimeout(ctx, importFetchTimeout)
+	defer cancel()
+
+	cal, err := ical.Fetch(ctx, s.client, calURL, s.loc)
+	if err != nil {
+		l.Warn("failed to fetch calendar", zap.String("url", calURL), zap.Error(err))
+		if errors.Is(err, ical.ErrNotCalendar) || errors.Is(err, ical.ErrTooLarge) {
+			return nil, ErrInvalidCalendar
+		}
+		if errors.Is(err, errPrivateAddress) {
+			return nil, ErrInvalidCalendarURL
+		}
+		return nil, ErrCalendarUnavailable
+	}
+	return cal, nil
+}
+
+func (s *SchedulesService) replaceImported(ctx context.Context, masterId int64, cal *ical.Calendar) (*models.CalendarImport, error) {
+	l := logger.FromContext(ctx)
+
+	now := time.Now()
+	from, to := now.Add(-importHistory), now.Add(importHorizon)
+
+	var blocks []models.TimeBlock
+	for _, ev := range cal.Events {
+		if !ev.IsBusy() {
+			continue
+		}
+		for _, inst := range ev.Instances(from, to) {
+			if !inst.End.After(inst.Start) {
+				continue
+			}
+			if len(blocks) == models.MaxImportedBlocks {
+				return nil, ValidationError{Msg: fmt.Sprintf("calendar has more than %d busy times in the next year", models.MaxImportedBlocks)}
+			}
+			blocks = append(blocks, models.TimeBlock{
+				MasterID: masterId,
+				StartsAt: naiveTime(inst.Start, s.loc),
+				EndsAt:   naiveTime(inst.End, s.loc),
+				Reason:   truncate(inst.Summary, models.MaxBlockReasonLen),
+				Source:   models.BlockSourceImport,
+			})
+		}
+	}
+
+	if err := s.repo.TimeBlocks.ReplaceImported(ctx, masterId, blocks); err != nil {
+		l.Error("failed to replace imported blocks", zap.Int64("master_id", masterId), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	l.Info("calendar imported", zap.Int64("master_id", masterId), zap.Int("blocks", len(blocks)))
+	importedAt := now
+	return &models.CalendarImport{MasterID: masterId, ImportedAt: &importedAt, Imported: len(blocks)}, nil
+}
+
+func normalizeCalendarURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return "", ErrInvalidCalendarURL
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "webcal", "webcals":
+		u.Scheme = "https"
+	case "http", "https":
+	default:
+		return "", ErrInvalidCalendarURL
+	}
+	return u.String(), nil
+}
+
+// checkPublicHost resolves the url's host and fails if any of its addresses
+// is not public. It gives a clear error early; the import client's dialer
+// enforces the same rule on every connection.
+func checkPublicHost(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return err
+	}
+	for _, a := range addrs {
+		if !isPublicIP(a.IP) {
+			return errPrivateAddress
+		}
+	}
+	return nil
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast())
+}
+
+// dialPublicOnly is a net.Dialer Control func refusing connections to
+// loopback, private, link-local and unspecified addresses. It sees the
+// resolved address, so redirects and DNS rebinding are covered too.
+func dialPublicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicIP(ip) {
+		return errPrivateAddress
+	}
+	return nil
+}
+
+// naiveTime converts t to wall clock time in loc as it is stored in TIMESTAMP columns.
+func naiveTime(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
+
+// newImportClient returns the client fetching published calendars. Unless
+// allowPrivate is set, it only connects to public addresses so that masters
+// can't make the server call internal services.
+func newImportClient(allowPrivate bool) *http.Client {
+	if allowPrivate {
+		return &http.Client{Timeout: importFetchTimeout}
+	}
+	dialer := &net.Dialer{Timeout: importFetchTimeout, Control: dialPublicOnly}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would make the dialer check the proxy instead of the calendar host.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: importFetchTimeout, Transport: transport}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strawberry/internal/models"
+	"strawberry/internal/repository"
+	"strawberry/pkg/ical"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testCalendar = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nEND:VCALENDAR\r\n"
+
+func TestImportClient_RejectsPrivateAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/calendar")
+		w.Write([]byte(testCalendar))
+	}))
+	defer srv.Close()
+
+	require.ErrorIs(t, checkPublicHost(context.Background(), srv.URL), errPrivateAddress)
+
+	_, err := ical.Fetch(context.Background(), newImportClient(false), srv.URL, time.UTC)
+	require.ErrorIs(t, err, errPrivateAddress)
+
+	_, err = ical.Fetch(context.Background(), newImportClient(true), srv.URL, time.UTC)
+	require.NoError(t, err)
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for addr, public := range map[string]bool{
+		"8.8.8.8":         true,
+		"2a00:1450::1":    true,
+		"127.0.0.1":       false,
+		"10.1.2.3":        false,
+		"172.18.0.5":      false,
+		"192.168.1.1":     false,
+		"169.254.169.254": false,
+		"0.0.0.0":         false,
+		"::1":             false,
+		"fe80::1":         false,
+		"fd00::1":         false,
+	} {
+		require.Equal(t, public, isPublicIP(net.ParseIP(addr)), addr)
+	}
+}
+
+func TestImportCalendar_TooManyBlocks(t *testing.T) {
+	users := &usersRepoMock{}
+	users.On("GetById", mock.Anything, int64(3)).Return(&models.User{Id: 3, Specialization: "nails"}, nil)
+
+	// Each daily event yields a block per day of the import window.
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\n")
+	start := time.Now().UTC().Format("20060102")
+	for i := 0; i < models.MaxImportedBlocks/365+1; i++ {
+		fmt.Fprintf(&b, "BEGIN:VEVENT\r\nDTSTART:%sT%02d0000Z\r\nDURATION:PT30M\r\nRRULE:FREQ=DAILY\r\nEND:VEVENT\r\n", start, i%24)
+	}
+	b.WriteString("END:VCALENDAR\r\n")
+
+	s := newSchedulesService(&repository.Repository{Users: users}, time.UTC, false)
+
+	_, err := s.ImportCalendar(context.Background(), 3, strings.NewReader(b.String()))
+
+	var valErr ValidationError
+	require.ErrorAs(t, err, &valErr)
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strawberry/internal/models"
+	"strawberry/internal/repository"
+	"strawberry/pkg/logger"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var ErrTemplateNotFound = errors.New("schedule template not found")
+
+// SetTemplate schedules a whole-week template taking effect from effectiveFrom.
+// Earlier templates are kept, so bookings before that date are not affected.
+// Booked appointments in the template's period that no longer fit are returned as conflicts.
+func (s *SchedulesService) SetTemplate(ctx context.Context, userId int64, effectiveFrom string, days map[string][]string) (*models.TemplateChange, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	from, err := time.Parse(DateFormat, effectiveFrom)
+	if err != nil {
+		return nil, ErrBadDate
+	}
+	if from.Before(s.today()) {
+		return nil, ValidationError{Msg: "template can't take effect in the past"}
+	}
+
+	normalized, err := validateWeek(days)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &models.WeeklyTemplate{EffectiveFrom: from.Format(DateFormat), Days: normalized}
+	if err := s.repo.Schedules.SaveTemplate(ctx, userId, t); err != nil {
+		l.Error("failed to save schedule template", zap.Int64("user_id", userId), zap.String("effective_from", t.EffectiveFrom), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	conflicts, err := s.templateConflicts(ctx, userId, from)
+	if err != nil {
+		return nil, err
+	}
+
+	l.Info("schedule template saved",
+		zap.Int64("user_id", userId),
+		zap.String("effective_from", t.EffectiveFrom),
+		zap.Int("conflicts", len(conflicts)),
+	)
+	return &models.TemplateChange{Template: t, Conflicts: conflicts}, nil
+}
+
+// SetWeek atomically replaces the weekly template in effect from effectiveFrom
+// (today when empty) and the given date overrides. It returns all future
+// appointments that don't fit the resulting schedule.
+func (s *SchedulesService) SetWeek(ctx context.Context, userId int64, effectiveFrom string, days map[string][]string, dates []models.DateSlots) (*models.TemplateChange, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	today := s.today()
+	from := today
+	if effectiveFrom != "" {
+		var err error
+		if from, err = time.Parse(DateFormat, effectiveFrom); err != nil {
+			return nil, ErrBadDate
+		}
+		if from.Before(today) {
+			return nil, ValidationError{Msg: "template can't take effect in the past"}
+		}
+	}
+
+	normalized, err := validateWeek(days)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(dates))
+	for i, d := range dates {
+		date, err := time.Parse(DateFormat, d.Date)
+		if err != nil {
+			return nil, ValidationError{Msg: fmt.Sprintf("invalid date: %s", d.Date)}
+		}
+		if date.Before(today) {
+			return nil, ValidationError{Msg: fmt.Sprintf("date %s is in the past", d.Date)}
+		}
+		if _, ok := seen[d.Date]; ok {
+			return nil, ValidationError{Msg: fmt.Sprintf("duplicate date: %s", d.Date)}
+		}
+		if err := validateSlots(d.Slots); err != nil {
+			return nil, ValidationError{Msg: fmt.Sprintf("%s: %s", d.Date, err)}
+		}
+		seen[d.Date] = struct{}{}
+		dates[i].Date = date.Format(DateFormat)
+	}
+
+	t := &models.WeeklyTemplate{EffectiveFrom: from.Format(DateFormat), Days: normalized}
+	if err := s.repo.Schedules.ReplaceWeek(ctx, userId, t, dates); err != nil {
+		l.Error("failed to replace week schedule", zap.Int64("user_id", userId), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	conflicts, err := s.unfitAppointments(ctx, userId, today, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	l.Info("week schedule replaced",
+		zap.Int64("user_id", userId),
+		zap.String("effective_from", t.EffectiveFrom),
+		zap.Int("dates", len(dates)),
+		zap.Int("conflicts", len(conflicts)),
+	)
+	return &models.TemplateChange{Template: t, Dates: dates, Conflicts: conflicts}, nil
+}
+
+func (s *SchedulesService) GetTemplates(ctx context.Context, userId int64) ([]models.WeeklyTemplate, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	templates, err := s.repo.Schedules.GetTemplates(ctx, userId)
+	if err != nil {
+		l.Error("failed to get schedule templates", zap.Int64("user_id", userId), zap.Error(err))
+		return nil, ErrInternal
+	}
+	return templates, nil
+}
+
+// DeleteTemplate removes a template that hasn't taken effect yet.
+func (s *SchedulesService) DeleteTemplate(ctx context.Context, userId int64, effectiveFrom string) error {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	from, err := time.Parse(DateFormat, effectiveFrom)
+	if err != nil {
+		return ErrBadDate
+	}
+	if !from.After(s.today()) {
+		return ValidationError{Msg: "only templates that haven't taken effect yet can be deleted"}
+	}
+
+	if err := s.repo.Schedules.DeleteTemplate(ctx, userId, from); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrTemplateNotFound
+		}
+		l.Error("failed to delete schedule template", zap.Int64("user_id", userId), zap.Error(err))
+		return ErrInternal
+	}
+	return nil
+}
+
+// templateConflicts lists active appointments from max(from, now) until the next
+// template takes effect that fall outside the working slots of their day.
+func (s *SchedulesService) templateConflicts(ctx context.Context, userId int64, from time.Time) ([]models.Appointment, error) {
+	l := logger.FromContext(ctx)
+
+	templates, err := s.repo.Schedules.GetTemplates(ctx, userId)
+	if err != nil {
+		l.Error("failed to get schedule templates", zap.Int64("user_id", userId), zap.Error(err))
+		return nil, ErrInternal
+	}
+	var until time.Time
+	for _, t := range templates {
+		d, err := time.Parse(DateFormat, t.EffectiveFrom)
+		if err == nil && d.After(from) {
+			until = d
+			break
+		}
+	}
+
+	return s.unfitAppointments(ctx, userId, from, until)
+}
+
+// unfitAppointments returns active appointments in [from, until) which are not in
+// the working slots of their day. A zero until means no upper bound.
+func (s *SchedulesService) unfitAppointments(ctx context.Context, userId int64, from, until time.Time) ([]models.Appointment, error) {
+	l := logger.FromContext(ctx)
+
+	now := naiveTime(time.Now(), s.loc)
+	if from.Before(now) {
+		from = now
+	}
+
+	apts, err := s.repo.Appointments.GetByMasterIdSince(ctx, userId, from)
+	if err != nil {
+		l.Error("failed to get master appointments", zap.Int64("user_id", userId), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	daySlots := map[string]map[string]bool{}
+	conflicts := []models.Appointment{}
+	for _, a := range apts {
+		if !until.IsZero() && !a.ScheduledAt.Before(until) {
+			break
+		}
+		if a.IsCanceled() || a.Status == models.StatusCompleted || a.Status == models.StatusNoShow {
+			continue
+		}
+
+		date := a.ScheduledAt.Format(DateFormat)
+		slots, ok := daySlots[date]
+		if !ok {
+			day := time.Date(a.ScheduledAt.Year(), a.ScheduledAt.Month(), a.ScheduledAt.Day(), 0, 0, 0, 0, time.UTC)
+			list, err := s.repo.Schedules.GetSlotsByDay(ctx, userId, day, strings.ToLower(day.Weekday().String()))
+			if err != nil {
+				l.Error("failed to get slots by day", zap.Int64("user_id", userId), zap.String("date", date), zap.Error(err))
+				return nil, ErrInternal
+			}
+			slots = make(map[string]bool, len(list))
+			for _, slot := range list {
+				slots[slot.Format("15:04")] = true
+			}
+			daySlots[date] = slots
+		}
+
+		if !slots[a.ScheduledAt.Format("15:04")] {
+			conflicts = append(conflicts, a)
+		}
+	}
+	return conflicts, nil
+}
+
+func (s *SchedulesService) today() time.Time {
+	now := time.Now().In(s.loc)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func isWeekDay(day string) bool {
+	for _, d := range models.WeekDays {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// validateWeek checks day names and slots of a weekly template, lowercasing the days.
+func validateWeek(days map[string][]string) (map[string][]string, error) {
+	normalized := make(map[string][]string, len(days))
+	for day, slots := range days {
+		day = strings.ToLower(day)
+		if !isWeekDay(day) {
+			return nil, ValidationError{Msg: fmt.Sprintf("not valid week day: %s", day)}
+		}
+		if _, ok := normalized[day]; ok {
+			return nil, ValidationError{Msg: fmt.Sprintf("duplicate week day: %s", day)}
+		}
+		if err := validateSlots(slots); err != nil {
+			return nil, ValidationError{Msg: fmt.Sprintf("%s: %s", day, err)}
+		}
+		normalized[day] = slots
+	}
+	return normalized, nil
+}
+
+// normalizeSlots parses slots of the single day endpoints, which accept
+// them in any order, and returns them as sorted HH:MM times without
+// duplicates.
+func normalizeSlots(slots []string) ([]string, error) {
+	times := make([]time.Time, 0, len(slots))
+	seen := make(map[time.Time]struct{}, len(slots))
+	for _, slot := range slots {
+		t, err := time.Parse("15:04", slot)
+		if err != nil {
+			return nil, ValidationError{Msg: fmt.Sprintf("invalid time slot format: %s", slot)}
+		}
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	res := make([]string, len(times))
+	for i, t := range times {
+		res[i] = t.Format("15:04")
+	}
+	return res, nil
+}
+
+// validateSlots checks slots are HH:MM times listed in ascending order without duplicates.
+func validateSlots(slots []string) error {
+	var prev time.Time
+	seen := make(map[string]struct{}, len(slots))
+	for i, slot := range slots {
+		t, err := time.Parse("15:04", slot)
+		if err != nil || len(slot) != len("15:04") {
+			return ValidationError{Msg: fmt.Sprintf("invalid time slot format: %s", slot)}
+		}
+		if _, ok := seen[slot]; ok {
+			return ValidationError{Msg: fmt.Sprintf("duplicate time slot: %s", slot)}
+		}
+		if i > 0 && !t.After(prev) {
+			return ValidationError{Msg: fmt.Sprintf("time slots must be in ascending order: %s goes after %s", slot, slots[i-1])}
+		}
+		seen[slot] = struct{}{}
+		prev = t
+	}
+	return nil
+}
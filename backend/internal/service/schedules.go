@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"strawberry/internal/models"
 	"strawberry/internal/repository"
 	"strawberry/pkg/logger"
@@ -20,11 +21,23 @@ var (
 )
 
 type SchedulesService struct {
-	repo *repository.Repository
+	repo   *repository.Repository
+	loc    *time.Location
+	client *http.Client
+	// allowPrivateURLs lets calendar urls point to non-public addresses.
+	allowPrivateURLs bool
 }
 
-func newSchedulesService(r *repository.Repository) *SchedulesService {
-	return &SchedulesService{repo: r}
+func newSchedulesService(r *repository.Repository, loc *time.Location, allowPrivateURLs bool) *SchedulesService {
+	if loc == nil {
+		loc = time.Local
+	}
+	return &SchedulesService{
+		repo:             r,
+		loc:              loc,
+		client:           newImportClient(allowPrivateURLs),
+		allowPrivateURLs: allowPrivateURLs,
+	}
 }
 
 func (s *SchedulesService) SetDayOff(ctx context.Context, userId int64, dateStr string, isDayOff bool) error {
@@ -51,24 +64,19 @@ func (s *SchedulesService) SetWorkingSlotsByWeekDay(ctx context.Context, userId
 	ctx = logger.WithLogger(ctx)
 	l := logger.FromContext(ctx)
 
-	validDays := map[string]struct{}{
-		"monday": {}, "tuesday": {}, "wednesday": {}, "thursday": {},
-		"friday": {}, "saturday": {}, "sunday": {},
-	}
-	if _, ok := validDays[dayOfWeek]; !ok {
+	if !isWeekDay(dayOfWeek) {
 		l.Error("validation failed")
 		return ValidationError{Msg: "not valid week day"}
 	}
 
-	for _, slot := range slots {
-		if _, err := time.Parse("15:04", slot); err != nil {
-			err = fmt.Errorf("invalid time slot format: %s", slot)
-			l.Error("validation failed", zap.String("slot", slot), zap.Error(err))
-			return ValidationError{Msg: err.Error()}
-		}
+	normalized, err := normalizeSlots(slots)
+	if err != nil {
+		l.Error("validation failed", zap.Strings("slots", slots), zap.Error(err))
+		return err
 	}
+	slots = normalized
 
-	err := s.repo.SetWorkingSlotsByWeekDay(ctx, userId, dayOfWeek, slots)
+	err = s.repo.SetWorkingSlotsByWeekDay(ctx, userId, dayOfWeek, slots)
 	if err != nil {
 		l.Error("failed to set working slots", zap.Int64("userID", userId), zap.String("dayOfWeek", dayOfWeek), zap.Any("slots", slots), zap.Error(err))
 		return err
@@ -87,6 +95,12 @@ func (s *SchedulesService) SetWorkingSlotsByDate(ctx context.Context, userId int
 		l.Warn("invalid date format", zap.Error(err))
 		return ErrBadDate
 	}
+	normalized, err := normalizeSlots(slots)
+	if err != nil {
+		l.Warn("validation failed", zap.Strings("slots", slots), zap.Error(err))
+		return err
+	}
+	slots = normalized
 
 	err = s.repo.Schedules.SetWorkingSlotsByDate(ctx, userId, dateFormatted, slots)
 	if err != nil {
@@ -181,16 +195,59 @@ func (s *SchedulesService) GetSchedule(ctx context.Context, date string, userId
 		appointmentStrs = append(appointmentStrs, a.ScheduledAt.Format("15:04"))
 	}
 
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	timeBlocks, err := s.repo.TimeBlocks.GetByRange(ctx, userId, dayStart, dayEnd)
+	if err != nil {
+		l.Error("Failed to get time blocks",
+			zap.Int64("user_id", userId),
+			zap.String("date", day.Format(DateFormat)),
+			zap.Error(err),
+		)
+	}
+	blocks := make([]models.ScheduleBlock, 0, len(timeBlocks))
+	for _, b := range timeBlocks {
+		blocks = append(blocks, dayBlock(b, dayStart, dayEnd))
+	}
+
+	held, err := s.repo.SlotHolds.GetHeldSlots(ctx, userId, dayStart)
+	if err != nil {
+		l.Error("Failed to get held slots",
+			zap.Int64("user_id", userId),
+			zap.String("date", day.Format(DateFormat)),
+			zap.Error(err),
+		)
+	}
+
 	l.Info("Successfully fetched today's schedule",
 		zap.Int64("user_id", userId),
 		zap.Strings("days_off", daysOff),
 		zap.Strings("slots", slotStrs),
 		zap.Strings("appointments", appointmentStrs),
+		zap.Int("blocks", len(blocks)),
 	)
 
 	return &models.TodaySchedule{
 		DaysOff:      daysOff,
 		Slots:        slotStrs,
 		Appointments: appointmentStrs,
+		Blocks:       blocks,
+		Held:         held,
 	}, nil
 }
+
+// dayBlock clips the block to [day, dayEnd); a block lasting past midnight ends at "24:00".
+func dayBlock(b models.TimeBlock, day, dayEnd time.Time) models.ScheduleBlock {
+	start, end := "00:00", "24:00"
+	if b.StartsAt.After(day) {
+		start = b.StartsAt.Format("15:04")
+	}
+	if b.EndsAt.Before(dayEnd) {
+		end = b.EndsAt.Format("15:04")
+	}
+	block := models.ScheduleBlock{ID: b.ID, Start: start, End: end, Source: b.Source}
+	if b.Source == models.BlockSourceManual {
+		block.Reason = b.Reason
+	}
+	return block
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"strawberry/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetWorkingSlotsByWeekDay_Unsorted(t *testing.T) {
+	schedules := &schedulesRepoMock{}
+	schedules.On("SetWorkingSlotsByWeekDay", mock.Anything, int64(2), "monday", []string{"09:00", "10:00", "12:30"}).Return(nil)
+
+	s := newSchedulesService(&repository.Repository{Schedules: schedules}, time.UTC, false)
+
+	err := s.SetWorkingSlotsByWeekDay(context.Background(), 2, "monday", []string{"12:30", "09:00", "10:00", "9:00"})
+
+	require.NoError(t, err)
+	schedules.AssertExpectations(t)
+}
+
+func TestValidateSlots_TemplateOrder(t *testing.T) {
+	require.NoError(t, validateSlots([]string{"09:00", "10:00"}))
+	require.Error(t, validateSlots([]string{"10:00", "09:00"}))
+	require.Error(t, validateSlots([]string{"09:00", "09:00"}))
+}
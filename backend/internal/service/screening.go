@@ -0,0 +1,221 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strawberry/internal/models"
+	"strawberry/internal/repository"
+	"strings"
+	"unicode"
+)
+
+// ReviewScreener is one check of the review screening pipeline. It reports
+// what it objects to; a review without findings passes the check.
+type ReviewScreener interface {
+	Screen(ctx context.Context, r *models.Review) ([]models.ScreeningFinding, error)
+}
+
+// ScreeningPipeline runs every screener over a review. New checks are
+// plugged in by appending them.
+type ScreeningPipeline []ReviewScreener
+
+func (p ScreeningPipeline) Run(ctx context.Context, r *models.Review) (models.ScreeningResult, error) {
+	res := models.ScreeningResult{Verdict: models.ScreeningAccept}
+	for _, s := range p {
+		findings, err := s.Screen(ctx, r)
+		if err != nil {
+			return res, err
+		}
+		for _, f := range findings {
+			res.Add(f)
+		}
+	}
+	return res, nil
+}
+
+// ReviewRejectedError is returned when screening refuses a review.
+type ReviewRejectedError struct {
+	Reasons []string
+}
+
+func (e ReviewRejectedError) Error() string {
+	return "review rejected: " + strings.Join(e.Reasons, "; ")
+}
+
+// ScreeningConfig configures the default screening pipeline.
+type ScreeningConfig struct {
+	// BannedWords reject a review that contains any of them.
+	BannedWords []string
+	// DuplicateLimit is how many other reviews with the same text get a
+	// review rejected; a single copy only hides it.
+	DuplicateLimit int
+}
+
+func newScreeningPipeline(r *repository.Repository, cfg ScreeningConfig) ScreeningPipeline {
+	if cfg.DuplicateLimit <= 0 {
+		cfg.DuplicateLimit = 3
+	}
+	return ScreeningPipeline{
+		NewWordListScreener(cfg.BannedWords),
+		NewContactScreener(),
+		&duplicateScreener{repo: r.Reviews, rejectAt: cfg.DuplicateLimit},
+	}
+}
+
+// ReadWordList reads a banned word list, one word or phrase per line. Blank
+// lines and lines starting with # are skipped.
+func ReadWordList(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var words []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	return words, sc.Err()
+}
+
+type wordListScreener struct {
+	words   map[string]bool
+	phrases []string
+}
+
+// NewWordListScreener rejects reviews containing any of the words. Words are
+// matched whole and case-insensitively. Entries of several words, including
+// hyphenated ones, match the same words in a row whatever separates them.
+// Entries without letters or digits are ignored.
+func NewWordListScreener(words []string) ReviewScreener {
+	s := &wordListScreener{words: make(map[string]bool, len(words))}
+	for _, w := range words {
+		switch tokens := screeningTokens(w); len(tokens) {
+		case 0:
+		case 1:
+			s.words[tokens[0]] = true
+		default:
+			s.phrases = append(s.phrases, " "+strings.Join(tokens, " ")+" ")
+		}
+	}
+	return s
+}
+
+// screeningTokens splits text into lowercase runs of letters and digits.
+func screeningTokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	})
+}
+
+func (s *wordListScreener) Screen(_ context.Context, r *models.Review) ([]models.ScreeningFinding, error) {
+	if len(s.words) == 0 && len(s.phrases) == 0 {
+		return nil, nil
+	}
+	tokens := screeningTokens(r.Comment)
+	found := false
+	for _, t := range tokens {
+		if s.words[t] {
+			found = true
+			break
+		}
+	}
+	if !found && len(s.phrases) > 0 {
+		text := " " + strings.Join(tokens, " ") + " "
+		for _, p := range s.phrases {
+			if strings.Contains(text, p) {
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+	return []models.ScreeningFinding{{
+		Check:   "word_list",
+		Verdict: models.ScreeningReject,
+		Reason:  "contains a prohibited word",
+	}}, nil
+}
+
+var (
+	linkPattern  = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+|\b[a-z0-9-]+\.(?:ru|su|com|net|org|io|me|info|biz|pro|online|site)\b|\bt\.me/\w+`)
+	phonePattern = regexp.MustCompile(`\+?\d[\d\s().-]{8,}\d`)
+)
+
+type contactScreener struct{}
+
+// NewContactScreener hides reviews with links or phone numbers until a
+// moderator checks they are not advertising.
+func NewContactScreener() ReviewScreener {
+	return contactScreener{}
+}
+
+func (contactScreener) Screen(_ context.Context, r *models.Review) ([]models.ScreeningFinding, error) {
+	var findings []models.ScreeningFinding
+	if linkPattern.MatchString(r.Comment) {
+		findings = append(findings, models.ScreeningFinding{
+			Check:   "contacts",
+			Verdict: models.ScreeningHide,
+			Reason:  "contains a link",
+		})
+	}
+	for _, m := range phonePattern.FindAllString(r.Comment, -1) {
+		digits := 0
+		for _, c := range m {
+			if c >= '0' && c <= '9' {
+				digits++
+			}
+		}
+		if digits >= 10 && digits <= 15 {
+			findings = append(findings, models.ScreeningFinding{
+				Check:   "contacts",
+				Verdict: models.ScreeningHide,
+				Reason:  "contains a phone number",
+			})
+			break
+		}
+	}
+	return findings, nil
+}
+
+// duplicateScreener catches the same text posted in several reviews.
+type duplicateScreener struct {
+	repo     repository.Reviews
+	rejectAt int
+}
+
+func (s *duplicateScreener) Screen(ctx context.Context, r *models.Review) ([]models.ScreeningFinding, error) {
+	fingerprint := models.CommentFingerprint(r.Comment)
+	if fingerprint == "" {
+		return nil, nil
+	}
+	n, err := s.repo.CountByFingerprint(ctx, fingerprint, r.Id)
+	if err != nil {
+		return nil, fmt.Errorf("count duplicate reviews: %w", err)
+	}
+	switch {
+	case n >= s.rejectAt:
+		return []models.ScreeningFinding{{
+			Check:   "duplicates",
+			Verdict: models.ScreeningReject,
+			Reason:  fmt.Sprintf("same text is already posted in %d reviews", n),
+		}}, nil
+	case n > 0:
+		return []models.ScreeningFinding{{
+			Check:   "duplicates",
+			Verdict: models.ScreeningHide,
+			Reason:  "same text is already posted in another review",
+		}}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strawberry/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWordListScreener(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	list := "# banned\nДурак\n\nразвод на деньги\nпо-хамски\n***\n"
+	require.NoError(t, os.WriteFile(path, []byte(list), 0o600))
+
+	words, err := ReadWordList(path)
+	require.NoError(t, err)
+	s := NewWordListScreener(words)
+
+	for comment, rejected := range map[string]bool{
+		"Мастер дурак!":                      true,
+		"Дураков тут нет":                    false,
+		"Это  РАЗВОД,\nна деньги":            true,
+		"развод и деньги":                    false,
+		"Ответили по-хамски":                 true,
+		"Ответили по хамски":                 true,
+		"Всё понравилось, спасибо за работу": false,
+	} {
+		findings, err := s.Screen(context.Background(), &models.Review{Comment: comment})
+		require.NoError(t, err)
+		if rejected {
+			require.Len(t, findings, 1, comment)
+			require.Equal(t, models.ScreeningReject, findings[0].Verdict)
+		} else {
+			require.Empty(t, findings, comment)
+		}
+	}
+}
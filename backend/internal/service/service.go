@@ -20,6 +20,20 @@ type Service struct {
 	File
 	Reviews
 	VerificationCode
+	Calendar
+	Idempotency
+}
+
+type Idempotency interface {
+	Begin(ctx context.Context, userId int64, key, fingerprint string) (*models.IdempotencyRecord, error)
+	Complete(ctx context.Context, userId int64, key string, rec *models.IdempotencyRecord) error
+	Abort(ctx context.Context, userId int64, key string) error
+}
+
+type Calendar interface {
+	GetFeedToken(ctx context.Context, userId int64) (string, error)
+	RotateFeedToken(ctx context.Context, userId int64) (string, error)
+	Feed(ctx context.Context, token string, asMaster bool) ([]byte, error)
 }
 
 type Schedules interface {
@@ -28,6 +42,19 @@ type Schedules interface {
 	SetWorkingSlotsByDate(ctx context.Context, userId int64, date string, slots []string) error
 	DeleteWorkingSlotsByDate(ctx context.Context, userId int64, date string) error
 	GetSchedule(ctx context.Context, date string, userId int64) (*models.TodaySchedule, error)
+
+	SetTemplate(ctx context.Context, userId int64, effectiveFrom string, days map[string][]string) (*models.TemplateChange, error)
+	GetTemplates(ctx context.Context, userId int64) ([]models.WeeklyTemplate, error)
+	DeleteTemplate(ctx context.Context, userId int64, effectiveFrom string) error
+	SetWeek(ctx context.Context, userId int64, effectiveFrom string, days map[string][]string, dates []models.DateSlots) (*models.TemplateChange, error)
+
+	ImportCalendar(ctx context.Context, masterId int64, data io.Reader) (*models.CalendarImport, error)
+	SetCalendarURL(ctx context.Context, masterId int64, url string) (*models.CalendarImport, error)
+	RefreshCalendar(ctx context.Context, masterId int64) (*models.CalendarImport, error)
+
+	CreateBlock(ctx context.Context, b *models.TimeBlock) (int64, error)
+	DeleteBlock(ctx context.Context, masterId int64, id int64) error
+	GetBlocks(ctx context.Context, masterId int64, from, to string) ([]models.TimeBlock, error)
 }
 
 type Users interface {
@@ -40,11 +67,11 @@ type Users interface {
 	GetById(ctx context.Context, id int64) (*models.User, error)
 	GetByFullName(ctx context.Context, fn string) ([]models.User, error)
 	GetByUsername(ctx context.Context, un string) (*models.User, error)
-	GetMastersByRating(ctx context.Context) ([]models.User, error)
-	GetMastersBySpecialization(ctx context.Context, s string) ([]models.User, error)
+	FindMasters(ctx context.Context, f models.MasterFilter) (*models.MasterPage, error)
+	Suggest(ctx context.Context, q models.SuggestQuery) ([]models.Suggestion, error)
 	Login(ctx context.Context, identifier string, pswrd string) (string, error)
 
-	Search(ctx context.Context, query string) ([]models.User, error)
+	Search(ctx context.Context, q models.SearchQuery) (*models.SearchPage, error)
 }
 
 type File interface {
@@ -65,13 +92,45 @@ type Appointments interface {
 	GetByMasterId(ctx context.Context, id int64) ([]models.Appointment, error)
 	GetByDate(ctx context.Context, id int64, date time.Time) ([]models.Appointment, error)
 	GetByStatus(ctx context.Context, status string) ([]models.Appointment, error)
+	UpdateStatus(ctx context.Context, id int64, masterId int64, status string) error
+	History(ctx context.Context, f models.HistoryFilter) (*models.HistoryPage, error)
+
+	CreateRecurring(ctx context.Context, a *models.Appointment, rule *models.Recurrence) (*models.AppointmentSeries, []int64, error)
+	CancelSeries(ctx context.Context, seriesId int64, userId int64) error
+
+	GetPolicy(ctx context.Context, masterId int64) (*models.CancellationPolicy, error)
+	SetPolicy(ctx context.Context, p *models.CancellationPolicy) error
+	GetClientStats(ctx context.Context, masterId int64, clientId int64) (*models.ClientStats, error)
+	RateClient(ctx context.Context, cr *models.ClientRating) error
+	ClientReliability(ctx context.Context, masterId int64, clientId int64) (*models.ClientReliability, error)
+
+	HoldSlot(ctx context.Context, h *models.SlotHold) error
+	ReleaseSlot(ctx context.Context, userId int64, masterId int64, at time.Time) error
+	SuggestAlternatives(ctx context.Context, userId int64, masterId int64, at time.Time, includeOthers bool) ([]models.SlotAlternative, error)
 }
 
 type Reviews interface {
 	Create(ctx context.Context, r *models.Review) error
 	Update(ctx context.Context, r *models.Review) error
-	GetByMasterId(ctx context.Context, masterId int64) ([]models.Review, error)
+	GetByMasterId(ctx context.Context, f models.ReviewFilter) (*models.ReviewPage, error)
+	Revisions(ctx context.Context, reviewId int64) ([]models.ReviewRevision, error)
 	Delete(ctx context.Context, userId, id int64) error
+	AwaitingReview(ctx context.Context, userId int64) ([]models.Appointment, error)
+
+	CreateReply(ctx context.Context, reply *models.ReviewReply) error
+	UpdateReply(ctx context.Context, reply *models.ReviewReply) error
+	DeleteReply(ctx context.Context, masterId int64, reviewId int64) error
+
+	AddPhoto(ctx context.Context, userId int64, reviewId int64, data io.Reader, size int64) (*models.ReviewPhoto, error)
+	GetPhoto(ctx context.Context, reviewId int64, photoId int64) (io.ReadCloser, *models.ReviewPhoto, error)
+	DeletePhoto(ctx context.Context, userId int64, reviewId int64, photoId int64) error
+
+	Report(ctx context.Context, rep *models.ReviewReport) error
+	ModerationQueue(ctx context.Context, moderatorId int64, status string) ([]models.ModerationCase, error)
+	Resolve(ctx context.Context, moderatorId int64, reviewId int64, decision string) error
+
+	Vote(ctx context.Context, v *models.ReviewVote) error
+	DeleteVote(ctx context.Context, userId int64, reviewId int64) error
 }
 
 type VerificationCode interface {
@@ -87,15 +146,27 @@ type Deps struct {
 	Minio           *minio_client.MinioClient
 	MailClient      mail.MailClient
 	VerificationTTL time.Duration
+	Location        *time.Location
+	HoldTTL         time.Duration
+	IdempotencyTTL  time.Duration
+	ModeratorIDs    []int64
+	HideThreshold   int
+	Screening       ScreeningConfig
+	SuggestTTL      time.Duration
+	// AllowPrivateCalendarURLs lets imported calendar urls point to
+	// loopback and private networks. Only for tests and local setups.
+	AllowPrivateCalendarURLs bool
 }
 
 func New(d *Deps) *Service {
 	return &Service{
-		Users:            newUsersService(d.Repository, d.JwtMgr, d.Hasher, d.MailClient),
-		Appointments:     newAppointmentsService(d.Repository, d.RabbitMq, d.MailClient),
-		Schedules:        newSchedulesService(d.Repository),
+		Users:            newUsersService(d.Repository, d.JwtMgr, d.Hasher, d.MailClient, d.Location, d.SuggestTTL),
+		Appointments:     newAppointmentsService(d.Repository, d.RabbitMq, d.MailClient, d.HoldTTL, d.Location),
+		Schedules:        newSchedulesService(d.Repository, d.Location, d.AllowPrivateCalendarURLs),
 		File:             newFileService(d.Minio),
-		Reviews:          newReviewsService(d.Repository, d.RabbitMq),
+		Reviews:          newReviewsService(d.Repository, d.RabbitMq, d.Minio, d.ModeratorIDs, d.HideThreshold, d.Screening),
 		VerificationCode: newVerificationCodeService(d.Repository, d.MailClient, d.VerificationTTL),
+		Calendar:         newCalendarService(d.Repository, d.Location),
+		Idempotency:      newIdempotencyService(d.Repository, d.IdempotencyTTL),
 	}
 }
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strawberry/internal/models"
+	"strawberry/internal/repository"
+	"strawberry/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrBlockNotFound = errors.New("time block not found")
+	ErrBlockConflict = errors.New("time block overlaps booked appointments")
+)
+
+// CreateBlock makes [StartsAt, EndsAt) unavailable for booking. The master's
+// slots stay untouched, so removing the block restores them.
+func (s *SchedulesService) CreateBlock(ctx context.Context, b *models.TimeBlock) (int64, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	b.Source = models.BlockSourceManual
+	if err := b.Validate(); err != nil {
+		return 0, ValidationError{Msg: err.Error()}
+	}
+	if err := ensureMaster(ctx, s.repo, b.MasterID); err != nil {
+		return 0, err
+	}
+
+	apts, err := s.repo.Appointments.GetByMasterIdSince(ctx, b.MasterID, b.StartsAt.Add(-models.AppointmentDuration))
+	if err != nil {
+		l.Error("failed to get master appointments", zap.Int64("master_id", b.MasterID), zap.Error(err))
+		return 0, ErrInternal
+	}
+	for _, a := range apts {
+		if !a.ScheduledAt.Before(b.EndsAt) {
+			break
+		}
+		if !a.IsCanceled() && b.Overlaps(a.ScheduledAt, a.ScheduledAt.Add(models.AppointmentDuration)) {
+			return 0, ErrBlockConflict
+		}
+	}
+
+	id, err := s.repo.TimeBlocks.Create(ctx, b)
+	if err != nil {
+		l.Error("failed to create time block", zap.Int64("master_id", b.MasterID), zap.Error(err))
+		return 0, ErrInternal
+	}
+
+	l.Info("time block created", zap.Int64("master_id", b.MasterID), zap.Int64("id", id))
+	return id, nil
+}
+
+func (s *SchedulesService) DeleteBlock(ctx context.Context, masterId int64, id int64) error {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	b, err := s.repo.TimeBlocks.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrBlockNotFound
+		}
+		l.Error("failed to get time block", zap.Int64("id", id), zap.Error(err))
+		return ErrInternal
+	}
+	if b.MasterID != masterId {
+		return ErrBlockNotFound
+	}
+	if b.Source != models.BlockSourceManual {
+		return ValidationError{Msg: "imported blocks are replaced by the next import"}
+	}
+
+	if err := s.repo.TimeBlocks.Delete(ctx, id); err != nil {
+		l.Error("failed to delete time block", zap.Int64("id", id), zap.Error(err))
+		return ErrInternal
+	}
+	return nil
+}
+
+// GetBlocks lists the master's blocks overlapping [from, to] dates including reasons of imported ones.
+func (s *SchedulesService) GetBlocks(ctx context.Context, masterId int64, from, to string) ([]models.TimeBlock, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	fromDate, err := time.Parse(DateFormat, from)
+	if err != nil {
+		return nil, ErrBadDate
+	}
+	toDate, err := time.Parse(DateFormat, to)
+	if err != nil || toDate.Before(fromDate) {
+		return nil, ErrBadDate
+	}
+
+	blocks, err := s.repo.TimeBlocks.GetByRange(ctx, masterId, fromDate, toDate.AddDate(0, 0, 1))
+	if err != nil {
+		l.Error("failed to get time blocks", zap.Int64("master_id", masterId), zap.Error(err))
+		return nil, ErrInternal
+	}
+	return blocks, nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"strawberry/internal/models"
 	"strawberry/internal/repository"
+	"strawberry/pkg/cursor"
 	hasher "strawberry/pkg/hash"
 	"strawberry/pkg/helper"
 	"strawberry/pkg/jwt"
@@ -35,18 +36,25 @@ func (v ValidationError) Error() string {
 }
 
 type UsersService struct {
-	r    *repository.Repository
-	j    jwt.JwtManager
-	h    *hasher.Hasher
-	mail mail.MailClient
+	r          *repository.Repository
+	j          jwt.JwtManager
+	h          *hasher.Hasher
+	mail       mail.MailClient
+	loc        *time.Location
+	suggestTTL time.Duration
 }
 
-func newUsersService(r *repository.Repository, j jwt.JwtManager, h *hasher.Hasher, mail mail.MailClient) Users {
+func newUsersService(r *repository.Repository, j jwt.JwtManager, h *hasher.Hasher, mail mail.MailClient, loc *time.Location, suggestTTL time.Duration) Users {
+	if loc == nil {
+		loc = time.Local
+	}
 	return &UsersService{
-		r:    r,
-		j:    j,
-		h:    h,
-		mail: mail,
+		r:          r,
+		j:          j,
+		h:          h,
+		mail:       mail,
+		loc:        loc,
+		suggestTTL: suggestTTL,
 	}
 }
 
@@ -128,7 +136,7 @@ func (s *UsersService) GetByFullName(ctx context.Context, fn string) ([]models.U
 		l.Error("failed to get users by full name", zap.Error(err))
 		return nil, ErrInternal
 	}
-	return s.enrichWithRatings(ctx, users), nil
+	return users, nil
 }
 
 func (s *UsersService) GetById(ctx context.Context, id int64) (*models.User, error) {
@@ -144,12 +152,10 @@ func (s *UsersService) GetById(ctx context.Context, id int64) (*models.User, err
 		return nil, ErrInternal
 	}
 
-	avgRating, err := s.r.Reviews.AverageRatingOfMaster(ctx, user.Id)
-	if err != nil {
-		l.Error("can't get average rating", zap.Error(err))
+	if err := s.addRatingTrend(ctx, user); err != nil {
+		l.Error("can't get rating", zap.Error(err))
 		return nil, ErrInternal
 	}
-	user.AverageRating = avgRating
 
 	return user, nil
 }
@@ -167,38 +173,33 @@ func (s *UsersService) GetByUsername(ctx context.Context, un string) (*models.Us
 		return nil, ErrInternal
 	}
 
-	avg, err := s.r.Reviews.AverageRatingOfMaster(ctx, user.Id)
-	if err != nil {
+	if err := s.addRatingTrend(ctx, user); err != nil {
 		l.Error("failed to get rating", zap.Error(err))
 		return nil, ErrInternal
 	}
-	user.AverageRating = avg
 
 	return user, nil
 }
 
-func (s *UsersService) GetMastersByRating(ctx context.Context) ([]models.User, error) {
+// FindMasters pages through the master catalogue with the filter applied
+// in one query.
+func (s *UsersService) FindMasters(ctx context.Context, f models.MasterFilter) (*models.MasterPage, error) {
 	ctx = logger.WithLogger(ctx)
 	l := logger.FromContext(ctx)
 
-	users, err := s.r.Users.GetMastersByRating(ctx)
-	if err != nil {
-		l.Error("failed to get masters by rating", zap.Error(err))
-		return nil, ErrInternal
+	if err := f.Normalize(); err != nil {
+		return nil, ValidationError{Msg: err.Error()}
 	}
-	return s.enrichWithRatings(ctx, users), nil
-}
 
-func (s *UsersService) GetMastersBySpecialization(ctx context.Context, spec string) ([]models.User, error) {
-	ctx = logger.WithLogger(ctx)
-	l := logger.FromContext(ctx)
-
-	users, err := s.r.Users.GetMastersBySpecialization(ctx, spec)
+	masters, total, err := s.r.Users.FindMasters(ctx, f, naiveTime(time.Now(), s.loc))
 	if err != nil {
-		l.Error("failed to get masters by specialization", zap.Error(err))
+		l.Error("failed to find masters", zap.String("specialization", f.Specialization), zap.Error(err))
 		return nil, ErrInternal
 	}
-	return s.enrichWithRatings(ctx, users), nil
+	if masters == nil {
+		masters = []models.User{}
+	}
+	return &models.MasterPage{Masters: masters, Total: total}, nil
 }
 
 func (s *UsersService) Login(ctx context.Context, identifier, pswrd string) (string, error) {
@@ -242,16 +243,79 @@ func (s *UsersService) Login(ctx context.Context, identifier, pswrd string) (str
 
 	return token, nil
 }
-func (s *UsersService) Search(ctx context.Context, query string) ([]models.User, error) {
+
+// Search pages through masters matching the query, most relevant and best
+// rated first.
+func (s *UsersService) Search(ctx context.Context, q models.SearchQuery) (*models.SearchPage, error) {
+	ctx = logger.WithLogger(ctx)
+	l := logger.FromContext(ctx)
+
+	if err := q.Normalize(); err != nil {
+		return nil, ValidationError{Msg: err.Error()}
+	}
+
+	var pos models.SearchCursor
+	if q.Cursor != "" {
+		if err := cursor.Decode(q.Cursor, &pos); err != nil {
+			return nil, ValidationError{Msg: err.Error()}
+		}
+		if pos.Offset < 0 {
+			return nil, ValidationError{Msg: cursor.ErrInvalidCursor.Error()}
+		}
+	}
+
+	users, err := s.r.Users.SearchUsers(ctx, q.Text, q.Limit+1, pos.Offset)
+	if err != nil {
+		l.Error("can't search users for query", zap.String("query", q.Text), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	page := &models.SearchPage{Masters: users}
+	if len(users) > q.Limit {
+		page.Masters = users[:q.Limit]
+		page.NextCursor, err = cursor.Encode(models.SearchCursor{Offset: pos.Offset + q.Limit})
+		if err != nil {
+			l.Error("failed to encode search cursor", zap.Error(err))
+			return nil, ErrInternal
+		}
+	}
+	if page.Masters == nil {
+		page.Masters = []models.User{}
+	}
+	return page, nil
+}
+
+// Suggest returns search bar suggestions for the prefix. Results are cached
+// for suggestTTL, so profile changes show up once the entry expires; cache
+// failures only cost a database query.
+func (s *UsersService) Suggest(ctx context.Context, q models.SuggestQuery) ([]models.Suggestion, error) {
 	ctx = logger.WithLogger(ctx)
 	l := logger.FromContext(ctx)
 
-	users, err := s.r.Users.SearchUsers(ctx, query)
+	if err := q.Normalize(); err != nil {
+		return nil, ValidationError{Msg: err.Error()}
+	}
+
+	cached, err := s.r.Suggestions.Get(ctx, q.Prefix, q.Limit)
+	if err == nil {
+		return cached, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		l.Warn("failed to get cached suggestions", zap.String("prefix", q.Prefix), zap.Error(err))
+	}
+
+	suggestions, err := s.r.Users.Suggest(ctx, q.Prefix, q.Limit)
 	if err != nil {
-		l.Error("can't search users for query", zap.Error(err))
+		l.Error("failed to get suggestions", zap.String("prefix", q.Prefix), zap.Error(err))
 		return nil, ErrInternal
 	}
-	return s.enrichWithRatings(ctx, users), nil
+	if suggestions == nil {
+		suggestions = []models.Suggestion{}
+	}
+	if err := s.r.Suggestions.Set(ctx, q.Prefix, q.Limit, suggestions, s.suggestTTL); err != nil {
+		l.Warn("failed to cache suggestions", zap.String("prefix", q.Prefix), zap.Error(err))
+	}
+	return suggestions, nil
 }
 
 func (s *UsersService) ChangePassword(ctx context.Context, email string, new_pswrd string) error {
@@ -284,12 +348,15 @@ func (s *UsersService) ChangePassword(ctx context.Context, email string, new_psw
 	return nil
 }
 
-func (s *UsersService) enrichWithRatings(ctx context.Context, users []models.User) []models.User {
-	for i := range users {
-		r, err := s.r.Reviews.AverageRatingOfMaster(ctx, users[i].Id)
-		if err == nil {
-			users[i].AverageRating = r
-		}
+// addRatingTrend completes a master's rating summary with recent reviews.
+func (s *UsersService) addRatingTrend(ctx context.Context, user *models.User) error {
+	if user.Rating == nil {
+		return nil
 	}
-	return users
+	count, sum, err := s.r.Reviews.RecentRating(ctx, user.Id, time.Now().Add(-models.RatingTrendWindow))
+	if err != nil {
+		return err
+	}
+	user.Rating.SetRecent(count, sum)
+	return nil
 }
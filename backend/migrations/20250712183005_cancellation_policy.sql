@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE appointments DROP CONSTRAINT IF EXISTS appointments_scheduled_at_key;
+
+ALTER TABLE appointments
+    ADD COLUMN canceled_at TIMESTAMP,
+    ADD COLUMN canceled_by INT REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS appointments_master_scheduled_active_idx
+    ON appointments (master_id, scheduled_at)
+    WHERE status NOT IN ('canceled', 'late_canceled');
+
+CREATE TABLE IF NOT EXISTS cancellation_policies (
+    master_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    free_cancel_hours INTEGER NOT NULL DEFAULT 24 CHECK (free_cancel_hours >= 0),
+    no_show_threshold INTEGER NOT NULL DEFAULT 3 CHECK (no_show_threshold >= 0),
+    require_confirmation BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS cancellation_policies;
+
+DROP INDEX IF EXISTS appointments_master_scheduled_active_idx;
+
+DELETE FROM appointments WHERE status IN ('canceled', 'late_canceled');
+
+ALTER TABLE appointments
+    DROP COLUMN canceled_at,
+    DROP COLUMN canceled_by,
+    ADD CONSTRAINT appointments_scheduled_at_key UNIQUE (scheduled_at);
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS schedule_templates (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    effective_from DATE NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, effective_from)
+);
+
+ALTER TABLE schedule_slots ADD COLUMN IF NOT EXISTS effective_from DATE NOT NULL DEFAULT DATE '1970-01-01';
+
+INSERT INTO schedule_templates (user_id, effective_from)
+SELECT DISTINCT user_id, DATE '1970-01-01' FROM schedule_slots
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE schedule_slots DROP CONSTRAINT IF EXISTS schedule_slots_user_id_day_of_week_slot_key;
+ALTER TABLE schedule_slots ADD CONSTRAINT schedule_slots_template_slot_key UNIQUE (user_id, effective_from, day_of_week, slot);
+ALTER TABLE schedule_slots ADD CONSTRAINT schedule_slots_template_fkey
+    FOREIGN KEY (user_id, effective_from) REFERENCES schedule_templates (user_id, effective_from) ON DELETE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE schedule_slots DROP CONSTRAINT IF EXISTS schedule_slots_template_fkey;
+ALTER TABLE schedule_slots DROP CONSTRAINT IF EXISTS schedule_slots_template_slot_key;
+DELETE FROM schedule_slots s
+WHERE s.effective_from IS DISTINCT FROM (
+    SELECT MAX(t.effective_from) FROM schedule_templates t
+    WHERE t.user_id = s.user_id AND t.effective_from <= CURRENT_DATE
+);
+ALTER TABLE schedule_slots ADD CONSTRAINT schedule_slots_user_id_day_of_week_slot_key UNIQUE (user_id, day_of_week, slot);
+ALTER TABLE schedule_slots DROP COLUMN IF EXISTS effective_from;
+DROP TABLE IF EXISTS schedule_templates;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS appointments_user_history_idx ON appointments (user_id, scheduled_at, id);
+CREATE INDEX IF NOT EXISTS appointments_master_history_idx ON appointments (master_id, scheduled_at, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS appointments_master_history_idx;
+DROP INDEX IF EXISTS appointments_user_history_idx;
+-- +goose StatementEnd
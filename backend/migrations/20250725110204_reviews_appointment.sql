@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS appointment_id INTEGER REFERENCES appointments(id) ON DELETE SET NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS reviews_appointment_id_key ON reviews (appointment_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS reviews_appointment_id_key;
+ALTER TABLE reviews DROP COLUMN IF EXISTS appointment_id;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS reviews_master_visible_created_idx ON reviews (master_id, created_at DESC, id DESC)
+    WHERE moderation = 'visible';
+CREATE INDEX IF NOT EXISTS reviews_master_visible_rating_idx ON reviews (master_id, rating, created_at DESC, id DESC)
+    WHERE moderation = 'visible';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS reviews_master_visible_rating_idx;
+DROP INDEX IF EXISTS reviews_master_visible_created_idx;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS helpful_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS unhelpful_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS review_votes (
+    review_id INTEGER NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    helpful BOOLEAN NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (review_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS reviews_master_visible_helpful_idx ON reviews (master_id, helpful_count DESC, created_at DESC, id DESC)
+    WHERE moderation = 'visible';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS reviews_master_visible_helpful_idx;
+DROP TABLE IF EXISTS review_votes;
+ALTER TABLE reviews
+    DROP COLUMN IF EXISTS unhelpful_count,
+    DROP COLUMN IF EXISTS helpful_count;
+-- +goose StatementEnd
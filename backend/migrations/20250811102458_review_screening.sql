@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS comment_fingerprint VARCHAR(40),
+    ADD COLUMN IF NOT EXISTS screening JSONB NOT NULL DEFAULT '[]';
+
+CREATE INDEX IF NOT EXISTS reviews_comment_fingerprint_idx ON reviews (comment_fingerprint)
+    WHERE comment_fingerprint IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS reviews_comment_fingerprint_idx;
+ALTER TABLE reviews
+    DROP COLUMN IF EXISTS screening,
+    DROP COLUMN IF EXISTS comment_fingerprint;
+-- +goose StatementEnd
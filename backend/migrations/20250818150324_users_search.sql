@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Names and bios are stemmed both as Russian and as English text since
+-- profiles mix the two; usernames are matched as is.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian', COALESCE(full_name, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(full_name, '')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE(username, '')), 'A') ||
+    setweight(to_tsvector('russian', COALESCE(specialization, '')), 'B') ||
+    setweight(to_tsvector('english', COALESCE(specialization, '')), 'B') ||
+    setweight(to_tsvector('russian', COALESCE(bio, '')), 'C') ||
+    setweight(to_tsvector('english', COALESCE(bio, '')), 'C')
+) STORED;
+
+-- search_text backs typo-tolerant trigram matching of the short fields.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS search_text TEXT GENERATED ALWAYS AS (
+    lower(COALESCE(full_name, '') || ' ' || COALESCE(username, '') || ' ' || COALESCE(specialization, ''))
+) STORED;
+
+CREATE INDEX IF NOT EXISTS users_search_vector_idx ON users USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS users_search_text_trgm_idx ON users USING GIN (search_text gin_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS users_search_text_trgm_idx;
+DROP INDEX IF EXISTS users_search_vector_idx;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS search_text,
+    DROP COLUMN IF EXISTS search_vector;
+-- +goose StatementEnd
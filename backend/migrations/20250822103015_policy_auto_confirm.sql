@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE cancellation_policies ADD COLUMN IF NOT EXISTS auto_confirm BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Masters who already saved a policy got their bookings confirmed right away.
+UPDATE cancellation_policies SET auto_confirm = TRUE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE cancellation_policies DROP COLUMN IF EXISTS auto_confirm;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+package cursor
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type position struct {
+	At time.Time `json:"at"`
+	ID int64     `json:"id"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	in := position{At: time.Date(2025, 6, 2, 14, 0, 0, 0, time.UTC), ID: 42}
+
+	token, err := Encode(in)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	var out position
+	if err := Decode(token, &out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !out.At.Equal(in.At) || out.ID != in.ID {
+		t.Errorf("expected %+v, got %+v", in, out)
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	var out position
+	for _, token := range []string{"%%%", "bm90IGpzb24"} {
+		if err := Decode(token, &out); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("token %q: expected ErrInvalidCursor, got %v", token, err)
+		}
+	}
+}
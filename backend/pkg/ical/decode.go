@@ -0,0 +1,478 @@
+package ical
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	MaxCalendarSize = 5 << 20
+
+	dateLayout     = "20060102"
+	maxInstances   = 1000
+	maxUnfoldLines = 1 << 20
+	// maxPeriods bounds the recurrence periods walked per event after
+	// skipping to the range; maxRecurringEvents bounds the events with a
+	// recurrence rule per calendar. Together they cap expansion work.
+	maxPeriods         = 2000
+	maxRecurringEvents = 1000
+)
+
+var (
+	ErrNotCalendar = errors.New("not an iCalendar document")
+	ErrTooLarge    = errors.New("calendar is too large")
+)
+
+// Decode parses VEVENTs of an iCalendar document. Floating times and unknown
+// TZIDs are interpreted in loc.
+func Decode(r io.Reader, loc *time.Location) (*Calendar, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	lines, err := unfold(io.LimitReader(r, MaxCalendarSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, ErrNotCalendar
+	}
+
+	cal := &Calendar{Location: loc}
+	var (
+		ev        *Event
+		depth     int
+		recurring int
+	)
+	for _, line := range lines[1:] {
+		p, err := parseProperty(line)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case p.name == "BEGIN" && p.value == "VEVENT" && depth == 0:
+			ev = &Event{}
+		case p.name == "BEGIN":
+			depth++
+		case p.name == "END" && p.value == "VEVENT" && depth == 0 && ev != nil:
+			if !ev.Start.IsZero() {
+				if ev.End.IsZero() {
+					ev.End = defaultEnd(ev)
+				}
+				if ev.RRule != "" {
+					if recurring++; recurring > maxRecurringEvents {
+						return nil, ErrTooLarge
+					}
+				}
+				cal.Events = append(cal.Events, *ev)
+			}
+			ev = nil
+		case p.name == "END" && p.value != "VCALENDAR" && depth > 0:
+			depth--
+		case ev != nil && depth == 0:
+			if err := ev.set(p, loc); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return cal, nil
+}
+
+// Fetch downloads and decodes a calendar published at url.
+func Fetch(ctx context.Context, client *http.Client, url string, loc *time.Location) (*Calendar, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/calendar")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status fetching calendar: %s", resp.Status)
+	}
+	return Decode(resp.Body, loc)
+}
+
+// Instances returns occurrences of the event overlapping [from, to).
+// Recurrence rules are expanded for DAILY, WEEKLY (with BYDAY) and MONTHLY
+// frequencies with INTERVAL, COUNT and UNTIL; other rules yield the first instance only.
+// At most maxInstances occurrences are returned, counted within the range.
+func (e *Event) Instances(from, to time.Time) []Event {
+	duration := e.End.Sub(e.Start)
+	overlaps := func(start time.Time) bool {
+		return start.Before(to) && start.Add(duration).After(from)
+	}
+	instance := func(start time.Time) Event {
+		inst := *e
+		inst.Start = start
+		inst.End = start.Add(duration)
+		inst.RRule = ""
+		inst.ExDates = nil
+		return inst
+	}
+
+	rule, err := parseRule(e.RRule)
+	if err != nil || rule == nil {
+		if overlaps(e.Start) {
+			return []Event{instance(e.Start)}
+		}
+		return nil
+	}
+
+	excluded := make(map[int64]bool, len(e.ExDates))
+	for _, d := range e.ExDates {
+		excluded[d.Unix()] = true
+	}
+
+	var res []Event
+	rule.starts(e.Start, from.Add(-duration), to, func(start time.Time, n int) bool {
+		if rule.count > 0 && n >= rule.count {
+			return false
+		}
+		if !rule.until.IsZero() && start.After(rule.until) {
+			return false
+		}
+		if excluded[start.Unix()] || !overlaps(start) {
+			return true
+		}
+		res = append(res, instance(start))
+		return len(res) < maxInstances
+	})
+	return res
+}
+
+func (e *Event) set(p property, loc *time.Location) error {
+	var err error
+	switch p.name {
+	case "UID":
+		e.UID = p.value
+	case "SUMMARY":
+		e.Summary = unescape(p.value)
+	case "DESCRIPTION":
+		e.Description = unescape(p.value)
+	case "STATUS":
+		e.Status = strings.ToUpper(p.value)
+	case "TRANSP":
+		e.Transparent = strings.EqualFold(p.value, "TRANSPARENT")
+	case "DTSTART":
+		e.Start, e.AllDay, err = parseTime(p, loc)
+	case "DTEND":
+		e.End, _, err = parseTime(p, loc)
+	case "DURATION":
+		var d time.Duration
+		if d, err = parseDuration(p.value); err == nil && !e.Start.IsZero() {
+			e.End = e.Start.Add(d)
+		}
+	case "RRULE":
+		e.RRule = p.value
+	case "EXDATE":
+		for _, v := range strings.Split(p.value, ",") {
+			var t time.Time
+			if t, _, err = parseTime(property{name: p.name, params: p.params, value: v}, loc); err != nil {
+				break
+			}
+			e.ExDates = append(e.ExDates, t)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", p.name, err)
+	}
+	return nil
+}
+
+func defaultEnd(e *Event) time.Time {
+	if e.AllDay {
+		return e.Start.AddDate(0, 0, 1)
+	}
+	return e.Start
+}
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+func parseProperty(line string) (property, error) {
+	p := property{params: map[string]string{}}
+
+	inQuotes := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		}
+		if r == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return p, fmt.Errorf("malformed content line: %q", line)
+	}
+	p.value = line[colon+1:]
+
+	parts := strings.Split(line[:colon], ";")
+	p.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		k, v, ok := strings.Cut(param, "=")
+		if !ok {
+			continue
+		}
+		p.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return p, nil
+}
+
+func parseTime(p property, loc *time.Location) (time.Time, bool, error) {
+	v := strings.TrimSpace(p.value)
+	if p.params["VALUE"] == "DATE" || len(v) == len(dateLayout) {
+		t, err := time.ParseInLocation(dateLayout, v, loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(v, "Z") {
+		t, err := time.Parse(dateTimeUTCLayout, v)
+		return t, false, err
+	}
+	if tzid := p.params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation(dateTimeLayout, v, loc)
+	return t, false, err
+}
+
+// parseDuration parses RFC 5545 durations such as PT1H30M, P1D or P2W.
+func parseDuration(v string) (time.Duration, error) {
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(v, "-"):
+		sign = -1
+		v = v[1:]
+	case strings.HasPrefix(v, "+"):
+		v = v[1:]
+	}
+	if !strings.HasPrefix(v, "P") {
+		return 0, fmt.Errorf("malformed duration %q", v)
+	}
+
+	var (
+		d      time.Duration
+		num    string
+		inTime bool
+	)
+	for _, r := range v[1:] {
+		switch {
+		case r >= '0' && r <= '9':
+			num += string(r)
+			continue
+		case r == 'T':
+			inTime = true
+			continue
+		}
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			return 0, fmt.Errorf("malformed duration %q", v)
+		}
+		num = ""
+		switch {
+		case r == 'W':
+			d += time.Duration(n) * 7 * 24 * time.Hour
+		case r == 'D':
+			d += time.Duration(n) * 24 * time.Hour
+		case r == 'H' && inTime:
+			d += time.Duration(n) * time.Hour
+		case r == 'M' && inTime:
+			d += time.Duration(n) * time.Minute
+		case r == 'S' && inTime:
+			d += time.Duration(n) * time.Second
+		default:
+			return 0, fmt.Errorf("malformed duration %q", v)
+		}
+	}
+	return sign * d, nil
+}
+
+type rule struct {
+	freq     string
+	interval int
+	count    int
+	until    time.Time
+	byDay    []time.Weekday
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+func parseRule(v string) (*rule, error) {
+	if v == "" {
+		return nil, nil
+	}
+	r := &rule{interval: 1}
+	for _, part := range strings.Split(v, ";") {
+		k, val, _ := strings.Cut(part, "=")
+		var err error
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			r.freq = strings.ToUpper(val)
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(val)
+		case "COUNT":
+			r.count, err = strconv.Atoi(val)
+		case "UNTIL":
+			r.until, _, err = parseTime(property{value: val}, time.UTC)
+		case "BYDAY":
+			for _, d := range strings.Split(val, ",") {
+				if wd, ok := weekdays[strings.ToUpper(d)]; ok {
+					r.byDay = append(r.byDay, wd)
+				} else {
+					return nil, fmt.Errorf("unsupported BYDAY %q", d)
+				}
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if r.interval < 1 {
+		r.interval = 1
+	}
+	switch r.freq {
+	case "DAILY", "WEEKLY", "MONTHLY":
+		return r, nil
+	}
+	return nil, fmt.Errorf("unsupported FREQ %q", r.freq)
+}
+
+// starts calls yield with occurrence starts from dtstart in chronological
+// order, along with their index in the series, until to or until yield
+// returns false. Periods well before from are skipped and only counted, and
+// at most maxPeriods periods are walked after that.
+func (r *rule) starts(dtstart, from, to time.Time, yield func(start time.Time, n int) bool) {
+	period, n := 0, 0
+	if from.After(dtstart) {
+		if skip := r.periodsBefore(dtstart, from); skip > 0 {
+			// All periods but the first one of a weekly BYDAY rule have
+			// the same number of occurrences.
+			n = len(r.candidates(dtstart, 0)) + (skip-1)*len(r.candidates(dtstart, 1))
+			period = skip
+		}
+	}
+	if r.count > 0 && n >= r.count {
+		return
+	}
+	for end := period + maxPeriods; period < end; period++ {
+		candidates := r.candidates(dtstart, period)
+		if len(candidates) == 0 {
+			continue
+		}
+		if !candidates[0].Before(to) {
+			return
+		}
+		for _, c := range candidates {
+			if c.Before(to) && !yield(c, n) {
+				return
+			}
+			n++
+		}
+	}
+}
+
+// candidates lists occurrence starts of the rule's period-th period.
+func (r *rule) candidates(dtstart time.Time, period int) []time.Time {
+	switch r.freq {
+	case "DAILY":
+		return []time.Time{dtstart.AddDate(0, 0, period*r.interval)}
+	case "MONTHLY":
+		return []time.Time{dtstart.AddDate(0, period*r.interval, 0)}
+	case "WEEKLY":
+		weekStart := dtstart.AddDate(0, 0, 7*period*r.interval)
+		if len(r.byDay) == 0 {
+			return []time.Time{weekStart}
+		}
+		var res []time.Time
+		monday := weekStart.AddDate(0, 0, -((int(weekStart.Weekday()) + 6) % 7))
+		for i := 0; i < 7; i++ {
+			day := monday.AddDate(0, 0, i)
+			for _, wd := range r.byDay {
+				if day.Weekday() == wd && !day.Before(dtstart) {
+					res = append(res, day)
+				}
+			}
+		}
+		return res
+	}
+	return nil
+}
+
+// periodsBefore returns a number of whole periods after dtstart that all end
+// before from. It stays one period short so that calendar irregularities
+// such as DST shifts and month lengths can't skip a needed occurrence.
+func (r *rule) periodsBefore(dtstart, from time.Time) int {
+	// Seconds instead of from.Sub, which saturates after 292 years.
+	days := int((from.Unix() - dtstart.Unix()) / (24 * 60 * 60))
+	var n int
+	switch r.freq {
+	case "DAILY":
+		n = days / r.interval
+	case "WEEKLY":
+		n = days / 7 / r.interval
+	case "MONTHLY":
+		months := (from.Year()-dtstart.Year())*12 + int(from.Month()) - int(dtstart.Month())
+		n = months / r.interval
+	}
+	if n--; n < 0 {
+		return 0
+	}
+	return n
+}
+
+func unfold(r io.Reader) ([]string, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), MaxCalendarSize)
+
+	var (
+		lines []string
+		size  int
+	)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		size += len(line) + 2
+		if size > MaxCalendarSize || len(lines) > maxUnfoldLines {
+			return nil, ErrTooLarge
+		}
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, ErrTooLarge
+		}
+		return nil, err
+	}
+	return lines, nil
+}
+
+func unescape(s string) string {
+	r := strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return r.Replace(s)
+}
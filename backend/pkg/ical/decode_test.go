@@ -0,0 +1,216 @@
+package ical
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sample = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VTIMEZONE\r\nTZID:Europe/Berlin\r\nBEGIN:STANDARD\r\nDTSTART:19701025T030000\r\nEND:STANDARD\r\nEND:VTIMEZONE\r\n" +
+	"BEGIN:VEVENT\r\nUID:utc\r\nDTSTART:20250602T070000Z\r\nDTEND:20250602T080000Z\r\nSUMMARY:Other\r\n  job\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nUID:tz\r\nDTSTART;TZID=Europe/Berlin:20250602T120000\r\nDURATION:PT1H30M\r\n" +
+	"BEGIN:VALARM\r\nTRIGGER:-PT15M\r\nEND:VALARM\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nUID:allday\r\nDTSTART;VALUE=DATE:20250603\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nUID:free\r\nDTSTART:20250604T100000\r\nDTEND:20250604T110000\r\nTRANSP:TRANSPARENT\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nUID:canceled\r\nDTSTART:20250604T100000\r\nDTEND:20250604T110000\r\nSTATUS:CANCELLED\r\nEND:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestDecode(t *testing.T) {
+	cal, err := Decode(strings.NewReader(sample), time.UTC)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if len(cal.Events) != 5 {
+		t.Fatalf("expected 5 events, got %d", len(cal.Events))
+	}
+
+	utc := cal.Events[0]
+	if !utc.Start.Equal(time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC)) || utc.Summary != "Other job" {
+		t.Errorf("unexpected event: %+v", utc)
+	}
+
+	if berlin, err := time.LoadLocation("Europe/Berlin"); err == nil {
+		tz := cal.Events[1]
+		if !tz.Start.Equal(time.Date(2025, 6, 2, 12, 0, 0, 0, berlin)) || tz.End.Sub(tz.Start) != 90*time.Minute {
+			t.Errorf("unexpected event: %+v", tz)
+		}
+	}
+
+	allDay := cal.Events[2]
+	if !allDay.AllDay || allDay.End.Sub(allDay.Start) != 24*time.Hour {
+		t.Errorf("unexpected all-day event: %+v", allDay)
+	}
+
+	for i, busy := range []bool{true, true, true, false, false} {
+		if cal.Events[i].IsBusy() != busy {
+			t.Errorf("event %s: expected busy=%v", cal.Events[i].UID, busy)
+		}
+	}
+}
+
+func TestDecode_NotCalendar(t *testing.T) {
+	if _, err := Decode(strings.NewReader("hello"), time.UTC); err != ErrNotCalendar {
+		t.Fatalf("expected ErrNotCalendar, got %v", err)
+	}
+}
+
+func TestDecode_RoundTrip(t *testing.T) {
+	start := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	err := (&Calendar{ProdID: "-//test//EN", Events: []Event{{UID: "1", Start: start, End: start.Add(time.Hour)}}}).Encode(&buf)
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+
+	cal, err := Decode(&buf, time.UTC)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if len(cal.Events) != 1 || !cal.Events[0].Start.Equal(start) {
+		t.Fatalf("unexpected events: %+v", cal.Events)
+	}
+}
+
+func TestInstances_Weekly(t *testing.T) {
+	start := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC) // Monday
+	ev := Event{
+		Start:   start,
+		End:     start.Add(time.Hour),
+		RRule:   "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=5",
+		ExDates: []time.Time{start.AddDate(0, 0, 7)},
+	}
+
+	got := ev.Instances(start.AddDate(0, 0, 1), start.AddDate(0, 1, 0))
+	want := []time.Time{
+		start.AddDate(0, 0, 2),
+		start.AddDate(0, 0, 9),
+		start.AddDate(0, 0, 14),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d instances, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if !got[i].Start.Equal(want[i]) || got[i].End.Sub(got[i].Start) != time.Hour {
+			t.Errorf("instance %d: got %v, want %v", i, got[i].Start, want[i])
+		}
+	}
+}
+
+func TestInstances_Until(t *testing.T) {
+	start := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	ev := Event{Start: start, End: start.Add(time.Hour), RRule: "FREQ=DAILY;INTERVAL=2;UNTIL=20250606T235959Z"}
+
+	if got := ev.Instances(start, start.AddDate(1, 0, 0)); len(got) != 3 {
+		t.Fatalf("expected 3 instances, got %d", len(got))
+	}
+}
+
+func TestFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/busy.ics" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/calendar")
+		w.Write([]byte(sample))
+	}))
+	defer srv.Close()
+
+	cal, err := Fetch(context.Background(), srv.Client(), srv.URL+"/busy.ics", time.UTC)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if len(cal.Events) != 5 {
+		t.Fatalf("expected 5 events, got %d", len(cal.Events))
+	}
+
+	if _, err := Fetch(context.Background(), srv.Client(), srv.URL+"/missing.ics", time.UTC); err == nil {
+		t.Fatal("expected error for missing calendar")
+	}
+}
+
+func TestInstances_LongRunning(t *testing.T) {
+	start := time.Date(2015, 3, 2, 9, 0, 0, 0, time.UTC)
+	from := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+
+	daily := Event{Start: start, End: start.Add(2 * time.Hour), RRule: "FREQ=DAILY"}
+	got := daily.Instances(from, to)
+	if len(got) != 7 {
+		t.Fatalf("expected 7 daily instances, got %d", len(got))
+	}
+	if want := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC); !got[0].Start.Equal(want) {
+		t.Errorf("first instance: got %v, want %v", got[0].Start, want)
+	}
+
+	weekly := Event{Start: start, End: start.Add(time.Hour), RRule: "FREQ=WEEKLY;BYDAY=MO,TH"}
+	if got := weekly.Instances(from, to); len(got) != 2 {
+		t.Fatalf("expected 2 weekly instances, got %d", len(got))
+	}
+
+	monthly := Event{Start: start, End: start.Add(time.Hour), RRule: "FREQ=MONTHLY"}
+	if got := monthly.Instances(from, from.AddDate(0, 3, 0)); len(got) != 3 {
+		t.Fatalf("expected 3 monthly instances, got %d", len(got))
+	}
+
+	counted := Event{Start: start, End: start.Add(time.Hour), RRule: "FREQ=DAILY;COUNT=3000"}
+	if got := counted.Instances(from, to); len(got) != 0 {
+		t.Fatalf("expected COUNT to end the series before 2025, got %d instances", len(got))
+	}
+}
+
+func TestInstances_CountFromFarPast(t *testing.T) {
+	from := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+
+	ancient := time.Date(1, 1, 1, 10, 0, 0, 0, time.UTC)
+	huge := Event{Start: ancient, End: ancient.Add(time.Hour), RRule: "FREQ=DAILY;COUNT=999999999"}
+	if got := huge.Instances(from, to); len(got) != 7 {
+		t.Fatalf("expected 7 instances, got %d", len(got))
+	}
+
+	spent := Event{Start: ancient, End: ancient.Add(time.Hour), RRule: "FREQ=DAILY;COUNT=5"}
+	if got := spent.Instances(from, to); len(got) != 0 {
+		t.Fatalf("expected no instances, got %d", len(got))
+	}
+
+	// Skipping ahead must count the same occurrences as walking from DTSTART.
+	start := time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC) // Wednesday
+	for _, rrule := range []string{"FREQ=WEEKLY;BYDAY=MO,WE;COUNT=150", "FREQ=DAILY;INTERVAL=3;COUNT=175", "FREQ=MONTHLY;COUNT=18"} {
+		ev := Event{Start: start, End: start.Add(time.Hour), RRule: rrule}
+		var want []time.Time
+		for _, inst := range ev.Instances(start, to) {
+			if inst.End.After(from) {
+				want = append(want, inst.Start)
+			}
+		}
+		got := ev.Instances(from, to)
+		if len(got) != len(want) || len(want) == 0 {
+			t.Fatalf("%s: expected %d instances, got %d", rrule, len(want), len(got))
+		}
+		for i := range want {
+			if !got[i].Start.Equal(want[i]) {
+				t.Errorf("%s: instance %d: got %v, want %v", rrule, i, got[i].Start, want[i])
+			}
+		}
+	}
+}
+
+func TestDecode_TooManyRecurringEvents(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\n")
+	for i := 0; i <= maxRecurringEvents; i++ {
+		b.WriteString("BEGIN:VEVENT\r\nDTSTART:00010101T100000Z\r\nRRULE:FREQ=DAILY;COUNT=999999999\r\nEND:VEVENT\r\n")
+	}
+	b.WriteString("END:VCALENDAR\r\n")
+
+	if _, err := Decode(strings.NewReader(b.String()), time.UTC); err != ErrTooLarge {
+		t.Fatalf("expected ErrTooLarge, got %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+	StatusCancelled = "CANCELLED"
+
+	dateTimeLayout    = "20060102T150405"
+	dateTimeUTCLayout = "20060102T150405Z"
+	maxLineOctets     = 75
+)
+
+type Calendar struct {
+	ProdID   string
+	Name     string
+	Location *time.Location
+	Events   []Event
+}
+
+type Event struct {
+	UID          string
+	Start        time.Time
+	End          time.Time
+	Summary      string
+	Description  string
+	Status       string
+	Sequence     int
+	Created      time.Time
+	LastModified time.Time
+
+	// Set by Decode only.
+	AllDay      bool
+	Transparent bool
+	RRule       string
+	ExDates     []time.Time
+}
+
+// IsBusy reports whether the event blocks time, i.e. it is neither canceled nor marked as free.
+func (e *Event) IsBusy() bool {
+	return e.Status != StatusCancelled && !e.Transparent
+}
+
+// Encode writes the calendar in RFC 5545 format. Event times are written as
+// local times of the calendar location, which is described by a VTIMEZONE.
+func (c *Calendar) Encode(w io.Writer) error {
+	loc := c.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	bw := bufio.NewWriter(w)
+	e := &encoder{w: bw}
+
+	e.line("BEGIN:VCALENDAR")
+	e.line("VERSION:2.0")
+	e.line("PRODID:" + c.ProdID)
+	e.line("CALSCALE:GREGORIAN")
+	e.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		e.line("X-WR-CALNAME:" + escape(c.Name))
+	}
+	e.line("X-WR-TIMEZONE:" + loc.String())
+	writeTimezone(e, loc, time.Now())
+
+	stamp := time.Now().UTC().Format(dateTimeUTCLayout)
+	for _, ev := range c.Events {
+		e.line("BEGIN:VEVENT")
+		e.line("UID:" + ev.UID)
+		e.line("DTSTAMP:" + stamp)
+		e.line(fmt.Sprintf("DTSTART;TZID=%s:%s", loc.String(), ev.Start.In(loc).Format(dateTimeLayout)))
+		e.line(fmt.Sprintf("DTEND;TZID=%s:%s", loc.String(), ev.End.In(loc).Format(dateTimeLayout)))
+		e.line("SUMMARY:" + escape(ev.Summary))
+		if ev.Description != "" {
+			e.line("DESCRIPTION:" + escape(ev.Description))
+		}
+		if ev.Status != "" {
+			e.line("STATUS:" + ev.Status)
+		}
+		e.line(fmt.Sprintf("SEQUENCE:%d", ev.Sequence))
+		if !ev.Created.IsZero() {
+			e.line("CREATED:" + ev.Created.UTC().Format(dateTimeUTCLayout))
+		}
+		if !ev.LastModified.IsZero() {
+			e.line("LAST-MODIFIED:" + ev.LastModified.UTC().Format(dateTimeUTCLayout))
+		}
+		e.line("END:VEVENT")
+	}
+	e.line("END:VCALENDAR")
+
+	if e.err != nil {
+		return e.err
+	}
+	return bw.Flush()
+}
+
+// writeTimezone describes loc with its standard and daylight observances of the given year.
+// Zones without DST get a single STANDARD observance.
+func writeTimezone(e *encoder, loc *time.Location, now time.Time) {
+	e.line("BEGIN:VTIMEZONE")
+	e.line("TZID:" + loc.String())
+
+	year := now.In(loc).Year()
+	winter := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	summer := time.Date(year, time.July, 1, 0, 0, 0, 0, loc)
+	winterName, winterOffset := winter.Zone()
+	summerName, summerOffset := summer.Zone()
+
+	if winterOffset == summerOffset {
+		writeObservance(e, "STANDARD", time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), winterOffset, winterOffset, winterName, "")
+	} else {
+		toSummer := transition(winter, summer)
+		toWinter := transition(summer, time.Date(year+1, time.January, 1, 0, 0, 0, 0, loc))
+
+		stdName, stdOffset, dstName, dstOffset := winterName, winterOffset, summerName, summerOffset
+		toDst, toStd := toSummer, toWinter
+		if summerOffset < winterOffset {
+			// southern hemisphere
+			stdName, stdOffset, dstName, dstOffset = summerName, summerOffset, winterName, winterOffset
+			toDst, toStd = toWinter, toSummer
+		}
+		writeObservance(e, "DAYLIGHT", toDst.In(time.FixedZone("", stdOffset)), stdOffset, dstOffset, dstName, yearlyRule(toDst.In(loc)))
+		writeObservance(e, "STANDARD", toStd.In(time.FixedZone("", dstOffset)), dstOffset, stdOffset, stdName, yearlyRule(toStd.In(loc)))
+	}
+
+	e.line("END:VTIMEZONE")
+}
+
+func writeObservance(e *encoder, kind string, start time.Time, from, to int, name, rrule string) {
+	e.line("BEGIN:" + kind)
+	e.line("DTSTART:" + start.Format(dateTimeLayout))
+	e.line("TZOFFSETFROM:" + formatOffset(from))
+	e.line("TZOFFSETTO:" + formatOffset(to))
+	if name != "" {
+		e.line("TZNAME:" + name)
+	}
+	if rrule != "" {
+		e.line("RRULE:" + rrule)
+	}
+	e.line("END:" + kind)
+}
+
+// transition finds the first instant in [from, to) whose UTC offset differs from the offset at from.
+func transition(from, to time.Time) time.Time {
+	_, offset := from.Zone()
+	lo, hi := from, to
+	for hi.Sub(lo) > time.Minute {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		if _, o := mid.Zone(); o == offset {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi.Truncate(time.Minute)
+}
+
+func yearlyRule(t time.Time) string {
+	week := (t.Day()-1)/7 + 1
+	if t.AddDate(0, 0, 7).Month() != t.Month() {
+		week = -1
+	}
+	day := strings.ToUpper(t.Weekday().String()[:2])
+	return fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYDAY=%d%s", int(t.Month()), week, day)
+}
+
+func formatOffset(seconds int) string {
+	sign := '+'
+	if seconds < 0 {
+		sign = '-'
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+func escape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line writes a content line folded at 75 octets without splitting UTF-8 sequences.
+func (e *encoder) line(s string) {
+	if e.err != nil {
+		return
+	}
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(s[cut]) {
+			cut--
+		}
+		if _, e.err = e.w.WriteString(s[:cut] + "\r\n "); e.err != nil {
+			return
+		}
+		s = s[cut:]
+		limit = maxLineOctets - 1
+	}
+	_, e.err = e.w.WriteString(s + "\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,89 @@
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncode_Event(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Skip("tzdata is not available")
+	}
+	start := time.Date(2025, 6, 2, 10, 0, 0, 0, loc)
+	cal := &Calendar{
+		ProdID:   "-//test//EN",
+		Location: loc,
+		Events: []Event{{
+			UID:     "appointment-1@test",
+			Start:   start,
+			End:     start.Add(time.Hour),
+			Summary: "Стрижка, окрашивание",
+			Status:  StatusCancelled,
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := cal.Encode(&buf); err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VTIMEZONE\r\nTZID:Europe/Moscow\r\n",
+		"TZOFFSETTO:+0300\r\n",
+		"UID:appointment-1@test\r\n",
+		"DTSTART;TZID=Europe/Moscow:20250602T100000\r\n",
+		"DTEND;TZID=Europe/Moscow:20250602T110000\r\n",
+		"SUMMARY:Стрижка\\, окрашивание\r\n",
+		"STATUS:CANCELLED\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q", want)
+		}
+	}
+}
+
+func TestEncode_FoldsLongLines(t *testing.T) {
+	cal := &Calendar{
+		ProdID: "-//test//EN",
+		Events: []Event{{UID: "1", Summary: strings.Repeat("ж", 100)}},
+	}
+
+	var buf bytes.Buffer
+	if err := cal.Encode(&buf); err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("line is longer than %d octets: %q", maxLineOctets, line)
+		}
+	}
+}
+
+func TestTimezone_Daylight(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("tzdata is not available")
+	}
+
+	var buf bytes.Buffer
+	e := &encoder{w: bufio.NewWriter(&buf)}
+	writeTimezone(e, loc, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+	e.w.Flush()
+	out := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:DAYLIGHT\r\nDTSTART:20250330T020000\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0200\r\n",
+		"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU\r\n",
+		"BEGIN:STANDARD\r\nDTSTART:20251026T030000\r\nTZOFFSETFROM:+0200\r\nTZOFFSETTO:+0100\r\n",
+		"RRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -144,3 +144,26 @@ func (m *MinioClient) DeleteWork(userId int64, workId string) error {
 	key := fmt.Sprintf("%d/%s", userId, workId)
 	return m.deleteObject(bucket, key)
 }
+
+func (m *MinioClient) UploadReviewPhoto(reviewId, photoId int64, reader io.Reader, size int64, contentType string) error {
+	bucket := "reviewsbonbontime"
+	key := fmt.Sprintf("%d/%d", reviewId, photoId)
+
+	if err := m.CreateBucket(bucket); err != nil {
+		return err
+	}
+
+	return m.UploadFile(bucket, key, reader, size, contentType)
+}
+
+func (m *MinioClient) GetReviewPhoto(reviewId, photoId int64) (io.ReadCloser, error) {
+	bucket := "reviewsbonbontime"
+	key := fmt.Sprintf("%d/%d", reviewId, photoId)
+	return m.DownloadFile(bucket, key)
+}
+
+func (m *MinioClient) DeleteReviewPhoto(reviewId, photoId int64) error {
+	bucket := "reviewsbonbontime"
+	key := fmt.Sprintf("%d/%d", reviewId, photoId)
+	return m.deleteObject(bucket, key)
+}
@@ -32,7 +32,7 @@ async def main():
                     routing_keys=["appointments.created", "appointments.deleted"])
     
     run_bot_consumer(bot, os.getenv("RABBITMQ_URL"), os.getenv("EXCHANGE_NAME_2"),
-                    routing_keys=["reviews.created"])
+                    routing_keys=["reviews.created", "reviews.replied"])
 
     days_off_router = create_days_off_router(api_client)
     schedule_router = create_schedule_router(api_client, jwt_decoder)
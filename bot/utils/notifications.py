@@ -41,6 +41,10 @@ async def on_message(message: IncomingMessage, bot: Bot):
                 text = (f"Новый отзыв от пользователя {payload.get('user_id')}:\n"
                         f"Оценка: {payload.get('rating')}/5\n"
                         f"Комментарий: {payload.get('message')}")
+            elif routing_key == "reviews.replied":
+                text = (f"Мастер ответил на ваш отзыв:\n"
+                        f"Оценка: {payload.get('rating')}/5\n"
+                        f"Ответ: {payload.get('reply')}")
             else:
                 text = payload.get("text", "Новое уведомление")
 